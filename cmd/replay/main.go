@@ -0,0 +1,74 @@
+// Command replay loads a save, replays the last session of its event journal on it and prints
+// the statistics of the resulting world as JSON.
+//
+// Usage:
+//
+//	replay <save db> <journal>
+package main
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/journal"
+	"github.com/dwethmar/vork/persistence"
+	"go.etcd.io/bbolt"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: replay <save db> <journal>")
+	}
+	db, err := bbolt.Open(args[0], 0600, &bbolt.Options{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open save: %w", err)
+	}
+	defer db.Close()
+	f, err := os.Open(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	// the session started on the world of the save
+	bus := event.NewBus()
+	stores := ecsys.NewStores()
+	ecs := ecsys.New(bus, stores)
+	p := persistence.New(persistence.Options{
+		Logger:   slog.Default(),
+		EventBus: bus,
+		Stores:   stores,
+		ECS:      ecs,
+	})
+	if err = p.Load(db); err != nil {
+		return fmt.Errorf("failed to load save: %w", err)
+	}
+	if err = ecs.BuildHierarchy(); err != nil {
+		return fmt.Errorf("failed to rebuild hierarchy: %w", err)
+	}
+
+	// dispatch the events per frame, as the game does
+	bus.SetMode(event.Queued)
+	n, err := journal.Replay(f, ecs, bus)
+	if err != nil {
+		return fmt.Errorf("failed to replay journal: %w", err)
+	}
+	log.Printf("replayed %d events", n)
+
+	stats, err := ecs.Stats().JSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode stats: %w", err)
+	}
+	fmt.Println(string(stats))
+	return nil
+}
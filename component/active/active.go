@@ -0,0 +1,42 @@
+package active
+
+import (
+	"encoding/gob"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+const Type = component.Type("active")
+
+var _ component.Component = &Active{}
+
+// Active is a component that takes an entity and its descendants out of the simulation
+// without deleting them. An entity without it is active, an entity is only
+// effectively active if all its ancestors are active as well.
+type Active struct {
+	I      uint          // ID
+	E      entity.Entity // Entity
+	Active bool
+}
+
+func New(e entity.Entity, active bool) *Active {
+	return &Active{
+		I:      0,
+		E:      e,
+		Active: active,
+	}
+}
+
+func Empty() *Active {
+	return &Active{}
+}
+
+func (a *Active) ID() uint              { return a.I }
+func (a *Active) SetID(i uint)          { a.I = i }
+func (a *Active) Type() component.Type  { return Type }
+func (a *Active) Entity() entity.Entity { return a.E }
+
+func init() {
+	gob.Register(Active{})
+}
@@ -0,0 +1,73 @@
+package active
+
+import "github.com/dwethmar/vork/component"
+
+const (
+	// CreatedEventType is the event type for when a component is created.
+	CreatedEventType = "active.created"
+	// UpdatedEventType is the event type for when a component is updated.
+	UpdatedEventType = "active.updated"
+	// DeletedEventType is the event type for when a component is deleted.
+	DeletedEventType = "active.deleted"
+)
+
+var (
+	_ component.Event = &CreatedEvent{}
+	_ component.Event = &UpdatedEvent{}
+	_ component.Event = &DeletedEvent{}
+
+	_ Event = &CreatedEvent{}
+	_ Event = &UpdatedEvent{}
+	_ Event = &DeletedEvent{}
+)
+
+// Event is a change in a component.
+type Event interface {
+	component.Event
+	Active() *Active
+}
+
+type CreatedEvent struct {
+	active Active
+}
+
+func NewCreatedEvent(active Active) *CreatedEvent {
+	return &CreatedEvent{active: active}
+}
+
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Active() *Active                { return &e.active }
+func (e *CreatedEvent) ComponentID() uint              { return e.active.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.active }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.active.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
+
+type UpdatedEvent struct {
+	active Active
+}
+
+func NewUpdatedEvent(active Active) *UpdatedEvent {
+	return &UpdatedEvent{active: active}
+}
+
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Active() *Active                { return &e.active }
+func (e *UpdatedEvent) ComponentID() uint              { return e.active.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.active }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.active.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
+
+type DeletedEvent struct {
+	active Active
+}
+
+func NewDeletedEvent(active Active) *DeletedEvent {
+	return &DeletedEvent{active: active}
+}
+
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Active() *Active                { return &e.active }
+func (e *DeletedEvent) ComponentID() uint              { return e.active.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.active }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.active.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
@@ -35,11 +35,12 @@ func NewCreatedEvent(controllable Controllable) *CreatedEvent {
 	return &CreatedEvent{controllable: controllable}
 }
 
-func (e *CreatedEvent) Event() string                 { return CreatedEventType }
-func (e *CreatedEvent) Controllable() *Controllable   { return &e.controllable }
-func (e *CreatedEvent) ComponentID() uint             { return e.controllable.ID() }
-func (e *CreatedEvent) ComponentType() component.Type { return e.controllable.Type() }
-func (e *CreatedEvent) Deleted() bool                 { return false }
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Controllable() *Controllable    { return &e.controllable }
+func (e *CreatedEvent) ComponentID() uint              { return e.controllable.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.controllable }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.controllable.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
 
 type UpdatedEvent struct {
 	controllable Controllable
@@ -49,11 +50,12 @@ func NewUpdatedEvent(controllable Controllable) *UpdatedEvent {
 	return &UpdatedEvent{controllable: controllable}
 }
 
-func (e *UpdatedEvent) Event() string                 { return UpdatedEventType }
-func (e *UpdatedEvent) Controllable() *Controllable   { return &e.controllable }
-func (e *UpdatedEvent) ComponentID() uint             { return e.controllable.ID() }
-func (e *UpdatedEvent) ComponentType() component.Type { return e.controllable.Type() }
-func (e *UpdatedEvent) Deleted() bool                 { return false }
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Controllable() *Controllable    { return &e.controllable }
+func (e *UpdatedEvent) ComponentID() uint              { return e.controllable.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.controllable }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.controllable.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
 
 type DeletedEvent struct {
 	controllable Controllable
@@ -63,8 +65,9 @@ func NewDeletedEvent(controllable Controllable) *DeletedEvent {
 	return &DeletedEvent{controllable: controllable}
 }
 
-func (e *DeletedEvent) Event() string                 { return DeletedEventType }
-func (e *DeletedEvent) Controllable() *Controllable   { return &e.controllable }
-func (e *DeletedEvent) ComponentID() uint             { return e.controllable.ID() }
-func (e *DeletedEvent) ComponentType() component.Type { return e.controllable.Type() }
-func (e *DeletedEvent) Deleted() bool                 { return true }
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Controllable() *Controllable    { return &e.controllable }
+func (e *DeletedEvent) ComponentID() uint              { return e.controllable.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.controllable }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.controllable.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
@@ -7,5 +7,7 @@ type Event interface {
 	event.Event
 	ComponentID() uint
 	ComponentType() Type
+	// Component returns the component as it was when the event was published.
+	Component() Component
 	Deleted() bool
 }
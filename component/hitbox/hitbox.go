@@ -8,6 +8,8 @@ import (
 
 const (
 	Type = component.Type("hitbox")
+	// TagIndex is the name of the index on the tag of a hitbox.
+	TagIndex = "hitbox.tag"
 )
 
 // Hitbox is a component that holds the hitbox of an entity.
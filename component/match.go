@@ -0,0 +1,62 @@
+package component
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+)
+
+// typeMatcher matches the events of components of the types, see MatchType.
+type typeMatcher []Type
+
+// MatchType returns a matcher that matches the events of components of the types.
+func MatchType(types ...Type) event.Matcher {
+	return typeMatcher(types)
+}
+
+// Match returns true if the event is an event of a component of one of the types.
+func (m typeMatcher) Match(e event.Event) bool {
+	ce, ok := e.(Event)
+	return ok && slices.Contains(m, ce.ComponentType())
+}
+
+// EventTypes returns the event types of the types, as registered with RegisterEventTypes.
+// The matcher is not indexed if the event types of one of the types are not registered.
+func (m typeMatcher) EventTypes() ([]string, bool) {
+	eventTypes.mu.RLock()
+	defer eventTypes.mu.RUnlock()
+	var types []string
+	for _, t := range m {
+		et, ok := eventTypes.byType[t]
+		if !ok {
+			return nil, false
+		}
+		types = append(types, et...)
+	}
+	return types, true
+}
+
+// eventTypes holds the event types that are published for the components of a type.
+var eventTypes = struct {
+	mu     sync.RWMutex
+	byType map[Type][]string
+}{byType: make(map[Type][]string)}
+
+// RegisterEventTypes registers the event types that are published for the components of the type,
+// so matchers of the type can be indexed by them. It is called when a component type is registered
+// with the ECS.
+func RegisterEventTypes(t Type, types ...string) {
+	eventTypes.mu.Lock()
+	defer eventTypes.mu.Unlock()
+	eventTypes.byType[t] = slices.Clone(types)
+}
+
+// MatchEntity returns a matcher that matches the events of components of the entity.
+func MatchEntity(en entity.Entity) event.MatcherFunc {
+	return func(e event.Event) bool {
+		ce, ok := e.(Event)
+		return ok && ce.Component().Entity() == en
+	}
+}
@@ -0,0 +1,73 @@
+package name
+
+import "github.com/dwethmar/vork/component"
+
+const (
+	// CreatedEventType is the event type for when a component is created.
+	CreatedEventType = "name.created"
+	// UpdatedEventType is the event type for when a component is updated.
+	UpdatedEventType = "name.updated"
+	// DeletedEventType is the event type for when a component is deleted.
+	DeletedEventType = "name.deleted"
+)
+
+var (
+	_ component.Event = &CreatedEvent{}
+	_ component.Event = &UpdatedEvent{}
+	_ component.Event = &DeletedEvent{}
+
+	_ Event = &CreatedEvent{}
+	_ Event = &UpdatedEvent{}
+	_ Event = &DeletedEvent{}
+)
+
+// Event is a change in a component.
+type Event interface {
+	component.Event
+	Name() *Name
+}
+
+type CreatedEvent struct {
+	name Name
+}
+
+func NewCreatedEvent(name Name) *CreatedEvent {
+	return &CreatedEvent{name: name}
+}
+
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Name() *Name                    { return &e.name }
+func (e *CreatedEvent) ComponentID() uint              { return e.name.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.name }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.name.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
+
+type UpdatedEvent struct {
+	name Name
+}
+
+func NewUpdatedEvent(name Name) *UpdatedEvent {
+	return &UpdatedEvent{name: name}
+}
+
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Name() *Name                    { return &e.name }
+func (e *UpdatedEvent) ComponentID() uint              { return e.name.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.name }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.name.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
+
+type DeletedEvent struct {
+	name Name
+}
+
+func NewDeletedEvent(name Name) *DeletedEvent {
+	return &DeletedEvent{name: name}
+}
+
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Name() *Name                    { return &e.name }
+func (e *DeletedEvent) ComponentID() uint              { return e.name.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.name }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.name.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
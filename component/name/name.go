@@ -0,0 +1,44 @@
+package name
+
+import (
+	"encoding/gob"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+const (
+	Type = component.Type("name")
+	// Index is the name of the unique index on the name of an entity.
+	Index = "name"
+)
+
+var _ component.Component = &Name{}
+
+// Name is a component that holds the unique name of an entity.
+type Name struct {
+	I    uint          // ID
+	E    entity.Entity // Entity
+	Name string
+}
+
+func New(e entity.Entity, name string) *Name {
+	return &Name{
+		I:    0,
+		E:    e,
+		Name: name,
+	}
+}
+
+func Empty() *Name {
+	return &Name{}
+}
+
+func (n *Name) ID() uint              { return n.I }
+func (n *Name) SetID(i uint)          { n.I = i }
+func (n *Name) Type() component.Type  { return Type }
+func (n *Name) Entity() entity.Entity { return n.E }
+
+func init() {
+	gob.Register(Name{})
+}
@@ -35,11 +35,12 @@ func NewCreatedEvent(position Position) *CreatedEvent {
 	return &CreatedEvent{position: position}
 }
 
-func (e *CreatedEvent) Event() string                 { return CreatedEventType }
-func (e *CreatedEvent) Position() *Position           { return &e.position }
-func (e *CreatedEvent) ComponentID() uint             { return e.position.ID() }
-func (e *CreatedEvent) ComponentType() component.Type { return e.position.Type() }
-func (e *CreatedEvent) Deleted() bool                 { return false }
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Position() *Position            { return &e.position }
+func (e *CreatedEvent) ComponentID() uint              { return e.position.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.position }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.position.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
 
 type UpdatedEvent struct {
 	position Position
@@ -49,11 +50,12 @@ func NewUpdatedEvent(position Position) *UpdatedEvent {
 	return &UpdatedEvent{position: position}
 }
 
-func (e *UpdatedEvent) Event() string                 { return UpdatedEventType }
-func (e *UpdatedEvent) Position() *Position           { return &e.position }
-func (e *UpdatedEvent) ComponentID() uint             { return e.position.ID() }
-func (e *UpdatedEvent) ComponentType() component.Type { return e.position.Type() }
-func (e *UpdatedEvent) Deleted() bool                 { return false }
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Position() *Position            { return &e.position }
+func (e *UpdatedEvent) ComponentID() uint              { return e.position.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.position }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.position.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
 
 type DeletedEvent struct {
 	position Position
@@ -63,8 +65,9 @@ func NewDeletedEvent(position Position) *DeletedEvent {
 	return &DeletedEvent{position: position}
 }
 
-func (e *DeletedEvent) Event() string                 { return DeletedEventType }
-func (e *DeletedEvent) Position() *Position           { return &e.position }
-func (e *DeletedEvent) ComponentID() uint             { return e.position.ID() }
-func (e *DeletedEvent) ComponentType() component.Type { return e.position.Type() }
-func (e *DeletedEvent) Deleted() bool                 { return true }
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Position() *Position            { return &e.position }
+func (e *DeletedEvent) ComponentID() uint              { return e.position.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.position }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.position.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
@@ -0,0 +1,73 @@
+package relation
+
+import "github.com/dwethmar/vork/component"
+
+const (
+	// CreatedEventType is the event type for when a component is created.
+	CreatedEventType = "relation.created"
+	// UpdatedEventType is the event type for when a component is updated.
+	UpdatedEventType = "relation.updated"
+	// DeletedEventType is the event type for when a component is deleted.
+	DeletedEventType = "relation.deleted"
+)
+
+var (
+	_ component.Event = &CreatedEvent{}
+	_ component.Event = &UpdatedEvent{}
+	_ component.Event = &DeletedEvent{}
+
+	_ Event = &CreatedEvent{}
+	_ Event = &UpdatedEvent{}
+	_ Event = &DeletedEvent{}
+)
+
+// Event is a change in a component.
+type Event interface {
+	component.Event
+	Relation() *Relation
+}
+
+type CreatedEvent struct {
+	relation Relation
+}
+
+func NewCreatedEvent(relation Relation) *CreatedEvent {
+	return &CreatedEvent{relation: relation}
+}
+
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Relation() *Relation            { return &e.relation }
+func (e *CreatedEvent) ComponentID() uint              { return e.relation.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.relation }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.relation.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
+
+type UpdatedEvent struct {
+	relation Relation
+}
+
+func NewUpdatedEvent(relation Relation) *UpdatedEvent {
+	return &UpdatedEvent{relation: relation}
+}
+
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Relation() *Relation            { return &e.relation }
+func (e *UpdatedEvent) ComponentID() uint              { return e.relation.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.relation }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.relation.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
+
+type DeletedEvent struct {
+	relation Relation
+}
+
+func NewDeletedEvent(relation Relation) *DeletedEvent {
+	return &DeletedEvent{relation: relation}
+}
+
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Relation() *Relation            { return &e.relation }
+func (e *DeletedEvent) ComponentID() uint              { return e.relation.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.relation }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.relation.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
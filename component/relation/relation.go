@@ -0,0 +1,78 @@
+package relation
+
+import (
+	"encoding/gob"
+	"fmt"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+const (
+	Type = component.Type("relation")
+	// SourceIndex is the name of the index on the kind of the relations of a source entity.
+	SourceIndex = "relation.source"
+	// TargetIndex is the name of the index on the target of a relation, by target and by kind and target.
+	TargetIndex = "relation.target"
+	// KeyIndex is the name of the unique index on the kind, source and target of a relation.
+	KeyIndex = "relation.key"
+)
+
+// Kind is the kind of a relation between two entities.
+type Kind string
+
+const (
+	Targets    = Kind("targets")     // The source targets the target.
+	OwnedBy    = Kind("owned_by")    // The source is owned by the target.
+	Follows    = Kind("follows")     // The source follows the target.
+	EquippedIn = Kind("equipped_in") // The source is equipped in a slot of the target.
+)
+
+var _ component.Component = &Relation{}
+
+// Relation is a component that relates its entity, the source, to a target entity.
+// Unlike the parent in a position, an entity can have any number of relations of any kind.
+type Relation struct {
+	I      uint          // ID
+	E      entity.Entity // Entity, the source of the relation
+	Kind   Kind
+	Target entity.Entity
+	Slot   string // Slot of the target, optional
+}
+
+func New(kind Kind, source, target entity.Entity) *Relation {
+	return &Relation{
+		I:      0,
+		E:      source,
+		Kind:   kind,
+		Target: target,
+	}
+}
+
+func Empty() *Relation {
+	return &Relation{}
+}
+
+func (r *Relation) ID() uint              { return r.I }
+func (r *Relation) SetID(i uint)          { r.I = i }
+func (r *Relation) Type() component.Type  { return Type }
+func (r *Relation) Entity() entity.Entity { return r.E }
+
+// Key returns the value of the relation in the KeyIndex.
+func (r *Relation) Key() string {
+	return Key(r.Kind, r.E, r.Target)
+}
+
+// Key returns the value of a relation of kind from source to target in the KeyIndex.
+func Key(kind Kind, source, target entity.Entity) string {
+	return fmt.Sprintf("%s/%d/%d", kind, source, target)
+}
+
+// TargetKey returns the value of a relation of kind to target in the TargetIndex.
+func TargetKey(kind Kind, target entity.Entity) string {
+	return fmt.Sprintf("%s/%d", kind, target)
+}
+
+func init() {
+	gob.Register(Relation{})
+}
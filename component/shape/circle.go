@@ -9,14 +9,14 @@ import (
 
 const CircleType = component.Type("shape-circle")
 
-var _ component.Component = &Rectangle{}
+var _ component.Component = &Circle{}
 
-// Shape is a component that holds the shape of an entity.
+// Circle is a component that draws a circle around the position of the entity.
 type Circle struct {
 	I      uint          // ID
 	E      entity.Entity // Entity
 	Radius int64
-	Color  color.RGBA
+	Style
 }
 
 func (p *Circle) ID() uint              { return p.I }
@@ -24,11 +24,12 @@ func (p *Circle) SetID(i uint)          { p.I = i }
 func (p *Circle) Type() component.Type  { return CircleType }
 func (p *Circle) Entity() entity.Entity { return p.E }
 
-func NewCircle(e entity.Entity, radius int64, color color.RGBA) *Circle {
+// NewCircle returns a circle that is filled with the colour.
+func NewCircle(e entity.Entity, radius int64, color color.NRGBA) *Circle {
 	return &Circle{
 		I:      0,
 		E:      e,
 		Radius: radius,
-		Color:  color,
+		Style:  Filled(color),
 	}
 }
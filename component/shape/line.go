@@ -0,0 +1,37 @@
+package shape
+
+import (
+	"image/color"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/point"
+)
+
+const LineType = component.Type("shape-line")
+
+var _ component.Component = &Line{}
+
+// Line is a component that draws a line from the position of the entity to a point relative to it.
+// A line is only stroked, its fill colour is ignored.
+type Line struct {
+	I  uint          // ID
+	E  entity.Entity // Entity
+	To point.Point
+	Style
+}
+
+func (p *Line) ID() uint              { return p.I }
+func (p *Line) SetID(i uint)          { p.I = i }
+func (p *Line) Type() component.Type  { return LineType }
+func (p *Line) Entity() entity.Entity { return p.E }
+
+// NewLine returns a line with the colour and width.
+func NewLine(e entity.Entity, to point.Point, color color.NRGBA, width float64) *Line {
+	return &Line{
+		I:     0,
+		E:     e,
+		To:    to,
+		Style: Stroked(color, width),
+	}
+}
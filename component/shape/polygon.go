@@ -0,0 +1,44 @@
+package shape
+
+import (
+	"image/color"
+	"slices"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/point"
+)
+
+const PolygonType = component.Type("shape-polygon")
+
+var _ component.Component = &Polygon{}
+
+// Polygon is a component that draws a closed polygon through points relative to the position of the entity.
+type Polygon struct {
+	I      uint          // ID
+	E      entity.Entity // Entity
+	Points []point.Point
+	Style
+}
+
+func (p *Polygon) ID() uint              { return p.I }
+func (p *Polygon) SetID(i uint)          { p.I = i }
+func (p *Polygon) Type() component.Type  { return PolygonType }
+func (p *Polygon) Entity() entity.Entity { return p.E }
+
+// Clone returns a copy of the polygon that does not share its points.
+func (p *Polygon) Clone() *Polygon {
+	c := *p
+	c.Points = slices.Clone(p.Points)
+	return &c
+}
+
+// NewPolygon returns a polygon through the points that is filled with the colour.
+func NewPolygon(e entity.Entity, color color.NRGBA, points ...point.Point) *Polygon {
+	return &Polygon{
+		I:      0,
+		E:      e,
+		Points: points,
+		Style:  Filled(color),
+	}
+}
@@ -11,12 +11,12 @@ const RectangleType = component.Type("shape-rectangle")
 
 var _ component.Component = &Rectangle{}
 
-// Shape is a component that holds the shape of an entity.
+// Rectangle is a component that draws a rectangle with its top left corner at the position of the entity.
 type Rectangle struct {
 	I             uint          // ID
 	E             entity.Entity // Entity
 	Width, Height int64
-	Color         color.RGBA
+	Style
 }
 
 func (p *Rectangle) ID() uint              { return p.I }
@@ -24,12 +24,13 @@ func (p *Rectangle) SetID(i uint)          { p.I = i }
 func (p *Rectangle) Type() component.Type  { return RectangleType }
 func (p *Rectangle) Entity() entity.Entity { return p.E }
 
-func NewRectangle(e entity.Entity, width, height int64, color color.RGBA) *Rectangle {
+// NewRectangle returns a rectangle that is filled with the colour.
+func NewRectangle(e entity.Entity, width, height int64, color color.NRGBA) *Rectangle {
 	return &Rectangle{
 		I:      0,
 		E:      e,
 		Width:  width,
 		Height: height,
-		Color:  color,
+		Style:  Filled(color),
 	}
 }
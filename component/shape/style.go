@@ -0,0 +1,33 @@
+package shape
+
+import "image/color"
+
+// Style holds how a shape is drawn. A shape is filled when the fill colour is not
+// fully transparent and outlined when the stroke width is larger than zero.
+// The colours are not premultiplied, their alpha makes the shape translucent:
+// color.NRGBA{R: 0xff, A: 0x80} is a half transparent, fully saturated red.
+type Style struct {
+	Fill        color.NRGBA
+	Stroke      color.NRGBA
+	StrokeWidth float64
+}
+
+// Filled returns a style that fills a shape with the colour.
+func Filled(c color.NRGBA) Style {
+	return Style{Fill: c}
+}
+
+// Stroked returns a style that outlines a shape with the colour and width.
+func Stroked(c color.NRGBA, width float64) Style {
+	return Style{Stroke: c, StrokeWidth: width}
+}
+
+// HasFill reports whether the shape is filled.
+func (s Style) HasFill() bool {
+	return s.Fill.A > 0
+}
+
+// HasStroke reports whether the shape is outlined.
+func (s Style) HasStroke() bool {
+	return s.StrokeWidth > 0 && s.Stroke.A > 0
+}
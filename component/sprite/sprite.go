@@ -9,7 +9,11 @@ var _ component.Component = &Sprite{}
 
 type Graphic string
 
-const Type = component.Type("sprite")
+const (
+	Type = component.Type("sprite")
+	// TagIndex is the name of the index on the tag of a sprite.
+	TagIndex = "sprite.tag"
+)
 
 // Sprite is a component that holds the sprite of an entity.
 type Sprite struct {
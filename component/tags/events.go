@@ -0,0 +1,73 @@
+package tags
+
+import "github.com/dwethmar/vork/component"
+
+const (
+	// CreatedEventType is the event type for when a component is created.
+	CreatedEventType = "tags.created"
+	// UpdatedEventType is the event type for when a component is updated.
+	UpdatedEventType = "tags.updated"
+	// DeletedEventType is the event type for when a component is deleted.
+	DeletedEventType = "tags.deleted"
+)
+
+var (
+	_ component.Event = &CreatedEvent{}
+	_ component.Event = &UpdatedEvent{}
+	_ component.Event = &DeletedEvent{}
+
+	_ Event = &CreatedEvent{}
+	_ Event = &UpdatedEvent{}
+	_ Event = &DeletedEvent{}
+)
+
+// Event is a change in a component.
+type Event interface {
+	component.Event
+	Tags() *Tags
+}
+
+type CreatedEvent struct {
+	tags Tags
+}
+
+func NewCreatedEvent(tags Tags) *CreatedEvent {
+	return &CreatedEvent{tags: tags}
+}
+
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Tags() *Tags                    { return &e.tags }
+func (e *CreatedEvent) ComponentID() uint              { return e.tags.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.tags }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.tags.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
+
+type UpdatedEvent struct {
+	tags Tags
+}
+
+func NewUpdatedEvent(tags Tags) *UpdatedEvent {
+	return &UpdatedEvent{tags: tags}
+}
+
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Tags() *Tags                    { return &e.tags }
+func (e *UpdatedEvent) ComponentID() uint              { return e.tags.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.tags }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.tags.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
+
+type DeletedEvent struct {
+	tags Tags
+}
+
+func NewDeletedEvent(tags Tags) *DeletedEvent {
+	return &DeletedEvent{tags: tags}
+}
+
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Tags() *Tags                    { return &e.tags }
+func (e *DeletedEvent) ComponentID() uint              { return e.tags.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.tags }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.tags.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
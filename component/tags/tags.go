@@ -0,0 +1,69 @@
+package tags
+
+import (
+	"encoding/gob"
+	"slices"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+const (
+	Type = component.Type("tags")
+	// Index is the name of the index on the tags of an entity.
+	Index = "tags"
+)
+
+var _ component.Component = &Tags{}
+
+// Tags is a component that holds the tags of an entity.
+type Tags struct {
+	I    uint          // ID
+	E    entity.Entity // Entity
+	Tags []string
+}
+
+func New(e entity.Entity, tags ...string) *Tags {
+	return &Tags{
+		I:    0,
+		E:    e,
+		Tags: tags,
+	}
+}
+
+func Empty() *Tags {
+	return &Tags{}
+}
+
+func (t *Tags) ID() uint              { return t.I }
+func (t *Tags) SetID(i uint)          { t.I = i }
+func (t *Tags) Type() component.Type  { return Type }
+func (t *Tags) Entity() entity.Entity { return t.E }
+
+// Has reports whether the entity is tagged with tag.
+func (t *Tags) Has(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// Add tags the entity with tag, if it is not tagged with it already.
+func (t *Tags) Add(tag string) {
+	if !t.Has(tag) {
+		t.Tags = append(t.Tags, tag)
+	}
+}
+
+// Remove removes tag from the tags of the entity.
+func (t *Tags) Remove(tag string) {
+	t.Tags = slices.DeleteFunc(t.Tags, func(s string) bool { return s == tag })
+}
+
+// Clone returns a deep copy of the tags.
+func (t *Tags) Clone() *Tags {
+	c := *t
+	c.Tags = slices.Clone(t.Tags)
+	return &c
+}
+
+func init() {
+	gob.Register(Tags{})
+}
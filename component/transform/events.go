@@ -0,0 +1,73 @@
+package transform
+
+import "github.com/dwethmar/vork/component"
+
+const (
+	// CreatedEventType is the event type for when a component is created.
+	CreatedEventType = "transform.created"
+	// UpdatedEventType is the event type for when a component is updated.
+	UpdatedEventType = "transform.updated"
+	// DeletedEventType is the event type for when a component is deleted.
+	DeletedEventType = "transform.deleted"
+)
+
+var (
+	_ component.Event = &CreatedEvent{}
+	_ component.Event = &UpdatedEvent{}
+	_ component.Event = &DeletedEvent{}
+
+	_ Event = &CreatedEvent{}
+	_ Event = &UpdatedEvent{}
+	_ Event = &DeletedEvent{}
+)
+
+// Event is a change in a component.
+type Event interface {
+	component.Event
+	Transform() *Transform
+}
+
+type CreatedEvent struct {
+	transform Transform
+}
+
+func NewCreatedEvent(transform Transform) *CreatedEvent {
+	return &CreatedEvent{transform: transform}
+}
+
+func (e *CreatedEvent) Event() string                  { return CreatedEventType }
+func (e *CreatedEvent) Transform() *Transform          { return &e.transform }
+func (e *CreatedEvent) ComponentID() uint              { return e.transform.ID() }
+func (e *CreatedEvent) Component() component.Component { return &e.transform }
+func (e *CreatedEvent) ComponentType() component.Type  { return e.transform.Type() }
+func (e *CreatedEvent) Deleted() bool                  { return false }
+
+type UpdatedEvent struct {
+	transform Transform
+}
+
+func NewUpdatedEvent(transform Transform) *UpdatedEvent {
+	return &UpdatedEvent{transform: transform}
+}
+
+func (e *UpdatedEvent) Event() string                  { return UpdatedEventType }
+func (e *UpdatedEvent) Transform() *Transform          { return &e.transform }
+func (e *UpdatedEvent) ComponentID() uint              { return e.transform.ID() }
+func (e *UpdatedEvent) Component() component.Component { return &e.transform }
+func (e *UpdatedEvent) ComponentType() component.Type  { return e.transform.Type() }
+func (e *UpdatedEvent) Deleted() bool                  { return false }
+
+type DeletedEvent struct {
+	transform Transform
+}
+
+func NewDeletedEvent(transform Transform) *DeletedEvent {
+	return &DeletedEvent{transform: transform}
+}
+
+func (e *DeletedEvent) Event() string                  { return DeletedEventType }
+func (e *DeletedEvent) Transform() *Transform          { return &e.transform }
+func (e *DeletedEvent) ComponentID() uint              { return e.transform.ID() }
+func (e *DeletedEvent) Component() component.Component { return &e.transform }
+func (e *DeletedEvent) ComponentType() component.Type  { return e.transform.Type() }
+func (e *DeletedEvent) Deleted() bool                  { return true }
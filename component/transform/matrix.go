@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"math"
+
+	"github.com/dwethmar/vork/point"
+)
+
+// Matrix is a 2D affine transformation that maps (x, y) to
+// (A*x + C*y + Tx, B*x + D*y + Ty).
+type Matrix struct {
+	A, B, C, D float64
+	Tx, Ty     float64
+}
+
+// Identity returns the matrix that does not transform.
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Local returns the local transform of an entity at position p with transform t:
+// it scales, then rotates and then translates. t may be nil.
+func Local(p point.Point, t *Transform) Matrix {
+	m := Identity()
+	if t != nil {
+		sin, cos := math.Sincos(t.Rotation)
+		sx, sy := t.Scale()
+		m = Matrix{
+			A: cos * sx,
+			B: sin * sx,
+			C: -sin * sy,
+			D: cos * sy,
+		}
+	}
+	m.Tx, m.Ty = float64(p.X), float64(p.Y)
+	return m
+}
+
+// Mul returns the matrix that applies n first and then m.
+func (m Matrix) Mul(n Matrix) Matrix {
+	return Matrix{
+		A:  m.A*n.A + m.C*n.B,
+		B:  m.B*n.A + m.D*n.B,
+		C:  m.A*n.C + m.C*n.D,
+		D:  m.B*n.C + m.D*n.D,
+		Tx: m.A*n.Tx + m.C*n.Ty + m.Tx,
+		Ty: m.B*n.Tx + m.D*n.Ty + m.Ty,
+	}
+}
+
+// Apply transforms the point (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.Tx, m.B*x + m.D*y + m.Ty
+}
+
+// Translation returns the point the origin is transformed to.
+func (m Matrix) Translation() (float64, float64) {
+	return m.Tx, m.Ty
+}
+
+// Point returns the translation rounded to the nearest point.
+func (m Matrix) Point() point.Point {
+	return point.New(int(math.Round(m.Tx)), int(math.Round(m.Ty)))
+}
+
+// Rotation returns the rotation in radians.
+func (m Matrix) Rotation() float64 {
+	return math.Atan2(m.B, m.A)
+}
+
+// Scale returns the scale along the x and y axis. The y scale is negative if the matrix mirrors.
+func (m Matrix) Scale() (float64, float64) {
+	sx := math.Hypot(m.A, m.B)
+	if sx == 0 {
+		return 0, math.Hypot(m.C, m.D)
+	}
+	return sx, (m.A*m.D - m.B*m.C) / sx
+}
@@ -0,0 +1,54 @@
+package transform
+
+import (
+	"encoding/gob"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+const Type = component.Type("transform")
+
+var _ component.Component = &Transform{}
+
+// Transform is a component that rotates and scales an entity around its position.
+// Together with the position it forms the local transform of the entity, which
+// applies to its descendants in the hierarchy as well. An entity without a
+// transform is neither rotated nor scaled. A scale of 0 collapses the entity and
+// its descendants, so use New or Empty rather than the zero value.
+type Transform struct {
+	I        uint          // ID
+	E        entity.Entity // Entity
+	Rotation float64       // Rotation in radians, clockwise on the screen.
+	ScaleX   float64       // Scale along the x axis.
+	ScaleY   float64       // Scale along the y axis.
+}
+
+func New(e entity.Entity, rotation, scaleX, scaleY float64) *Transform {
+	return &Transform{
+		I:        0,
+		E:        e,
+		Rotation: rotation,
+		ScaleX:   scaleX,
+		ScaleY:   scaleY,
+	}
+}
+
+// Scale returns the scale along both axes.
+func (t *Transform) Scale() (float64, float64) {
+	return t.ScaleX, t.ScaleY
+}
+
+// Empty returns a transform that neither rotates nor scales.
+func Empty() *Transform {
+	return &Transform{ScaleX: 1, ScaleY: 1}
+}
+
+func (t *Transform) ID() uint              { return t.I }
+func (t *Transform) SetID(i uint)          { t.I = i }
+func (t *Transform) Type() component.Type  { return Type }
+func (t *Transform) Entity() entity.Entity { return t.E }
+
+func init() {
+	gob.Register(Transform{})
+}
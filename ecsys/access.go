@@ -0,0 +1,39 @@
+package ecsys
+
+import (
+	"slices"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/resource"
+)
+
+// Access declares the component and resource types a system reads and writes.
+// Systems whose access does not conflict can run concurrently.
+type Access struct {
+	Reads          []component.Type
+	Writes         []component.Type
+	ReadResources  []resource.Type
+	WriteResources []resource.Type
+}
+
+// Conflicts reports whether the access conflicts with the other access,
+// which is the case when one of them writes a component or resource type the other reads or writes.
+func (a Access) Conflicts(o Access) bool {
+	return conflicts(a.Reads, a.Writes, o.Reads, o.Writes) ||
+		conflicts(a.ReadResources, a.WriteResources, o.ReadResources, o.WriteResources)
+}
+
+// conflicts reports whether one side writes a type the other side reads or writes.
+func conflicts[T comparable](aReads, aWrites, bReads, bWrites []T) bool {
+	for _, t := range aWrites {
+		if slices.Contains(bReads, t) || slices.Contains(bWrites, t) {
+			return true
+		}
+	}
+	for _, t := range bWrites {
+		if slices.Contains(aReads, t) {
+			return true
+		}
+	}
+	return false
+}
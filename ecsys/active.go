@@ -0,0 +1,130 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dwethmar/vork/component/active"
+	"github.com/dwethmar/vork/entity"
+)
+
+// activity caches whether entities are effectively active. The state of an entity
+// is invalidated when the active component of the entity or one of its ancestors changes.
+// Its lock is never held while other locks are taken.
+type activity struct {
+	mu    sync.RWMutex
+	cache map[entity.Entity]bool
+	gen   uint64 // advanced on every invalidation, so states computed meanwhile are not cached
+}
+
+func newActivity() *activity {
+	return &activity{cache: make(map[entity.Entity]bool)}
+}
+
+// SetActive sets whether the entity itself is active. Inactive entities and their descendants
+// are skipped by queries, iterators and the All functions unless IncludeInactive is passed,
+// but keep their components. The change is stored in the active component of the entity.
+func (s *ECS) SetActive(e entity.Entity, a bool) error {
+	if !s.Alive(e) {
+		return fmt.Errorf("could not set active state of entity %v: %w", e, ErrEntityNotFound)
+	}
+	c, err := Get[active.Active](s, e)
+	switch {
+	case errors.Is(err, ErrEntityNotFound):
+		if a {
+			return nil // Entities without an active component are active.
+		}
+		_, err = Add(s, *active.New(e, a))
+		return err
+	case err != nil:
+		return fmt.Errorf("could not set active state of entity %v: %w", e, err)
+	case c.Active == a:
+		return nil
+	}
+	c.Active = a
+	return Update(s, c)
+}
+
+// ActiveSelf reports whether the entity itself is active, regardless of its ancestors.
+func (s *ECS) ActiveSelf(e entity.Entity) bool {
+	c, err := Get[active.Active](s, e)
+	return err != nil || c.Active
+}
+
+// Active reports whether the entity and all its ancestors are active.
+// Cached states are read under a read lock, so systems can look them up concurrently.
+// A state that is not cached is computed without holding the lock of the cache, so Active
+// can be called while the lock of a store is held, for example while iterating.
+func (s *ECS) Active(e entity.Entity) bool {
+	if e == s.hierarchy.Root() {
+		return true
+	}
+	a, ok, gen := s.activity.get(e)
+	if ok {
+		return a
+	}
+	a = s.ActiveSelf(e)
+	if a {
+		// Entities without a position are not in the hierarchy and have no ancestors.
+		if parent, err := s.hierarchy.Parent(e); err == nil {
+			a = s.Active(parent)
+		}
+	}
+	s.activity.set(e, a, gen)
+	return a
+}
+
+// get returns the cached state of the entity and the generation of the cache.
+func (c *activity) get(e entity.Entity) (active, ok bool, gen uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	active, ok = c.cache[e]
+	return active, ok, c.gen
+}
+
+// set caches the state of the entity, unless the cache was invalidated after generation gen.
+func (c *activity) set(e entity.Entity, active bool, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.cache[e] = active
+	}
+}
+
+// invalidate removes the active states of the entities from the cache.
+func (c *activity) invalidate(entities []entity.Entity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for _, e := range entities {
+		delete(c.cache, e)
+	}
+}
+
+// reset empties the cache of active states.
+func (c *activity) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	clear(c.cache)
+}
+
+// invalidate removes the cached world transforms and active states of the entity and its descendants.
+func (s *ECS) invalidate(e entity.Entity) {
+	entities := append([]entity.Entity{e}, s.hierarchy.Descendants(e)...)
+	s.worlds.invalidate(entities)
+	s.activity.invalidate(entities)
+}
+
+// resetCaches empties the caches of world transforms and active states.
+func (s *ECS) resetCaches() {
+	s.worlds.reset()
+	s.activity.reset()
+}
+
+// invalidateEntityActive is the hook of the active component.
+func invalidateEntityActive(s *ECS, a *active.Active) error {
+	s.invalidate(a.Entity())
+	return nil
+}
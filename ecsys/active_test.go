@@ -0,0 +1,256 @@
+package ecsys_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dwethmar/vork/component/active"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTree creates a parent with a child and a grandchild.
+func newTree(t *testing.T, ecs *ecsys.ECS) (entity.Entity, entity.Entity, entity.Entity) {
+	t.Helper()
+	parent, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	child, err := ecs.CreateEntity(parent, point.Zero())
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	grandchild, err := ecs.CreateEntity(child, point.Zero())
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	return parent, child, grandchild
+}
+
+func TestECS_SetActive(t *testing.T) {
+	t.Run("should deactivate the subtree of the entity", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, child, grandchild := newTree(t, ecs)
+
+		if err := ecs.SetActive(child, false); err != nil {
+			t.Fatalf("SetActive() error = %v", err)
+		}
+		want := map[entity.Entity]bool{parent: true, child: false, grandchild: false}
+		for e, a := range want {
+			if got := ecs.Active(e); got != a {
+				t.Errorf("Active(%v) = %v, want %v", e, got, a)
+			}
+		}
+		if !ecs.ActiveSelf(grandchild) {
+			t.Errorf("ActiveSelf(%v) = false, want true", grandchild)
+		}
+
+		if err := ecs.SetActive(child, true); err != nil {
+			t.Fatalf("SetActive() error = %v", err)
+		}
+		if !ecs.Active(grandchild) {
+			t.Errorf("Active(%v) = false, want true", grandchild)
+		}
+	})
+
+	t.Run("should follow the entity when it is reparented", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, child, grandchild := newTree(t, ecs)
+		if err := ecs.SetActive(parent, false); err != nil {
+			t.Fatalf("SetActive() error = %v", err)
+		}
+		if ecs.Active(grandchild) {
+			t.Fatalf("Active(%v) = true, want false", grandchild)
+		}
+
+		pos, err := ecsys.Get[position.Position](ecs, child)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		pos.Parent = ecs.Root()
+		if err = ecsys.Update(ecs, pos); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if !ecs.Active(grandchild) {
+			t.Errorf("Active(%v) = false, want true", grandchild)
+		}
+	})
+
+	t.Run("should publish an event when toggled", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		var got []string
+		bus.Subscribe(event.MatchAny(active.CreatedEventType, active.UpdatedEventType), func(ev event.Event) error {
+			got = append(got, ev.Event())
+			return nil
+		})
+
+		for _, a := range []bool{true, false, false, true} {
+			if err := ecs.SetActive(e, a); err != nil {
+				t.Fatalf("SetActive() error = %v", err)
+			}
+		}
+		want := []string{active.CreatedEventType, active.UpdatedEventType}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("events mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should restore the state on rollback", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, _, grandchild := newTree(t, ecs)
+		errRollback := errors.New("roll back")
+		err := ecs.Tx(func(tx *ecsys.ECS) error {
+			if err := tx.SetActive(parent, false); err != nil {
+				return err
+			}
+			if tx.Active(grandchild) {
+				t.Errorf("Active(%v) = true, want false", grandchild)
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Tx() error = %v, want %v", err, errRollback)
+		}
+		if !ecs.Active(grandchild) {
+			t.Errorf("Active(%v) = false, want true", grandchild)
+		}
+	})
+
+	t.Run("should return an error if the entity is not alive", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		if err := ecs.DeleteEntity(e); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if err := ecs.SetActive(e, false); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("SetActive() error = %v, want %v", err, ecsys.ErrEntityNotFound)
+		}
+	})
+}
+
+func TestECS_InactiveEntities(t *testing.T) {
+	ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+	parent, child, grandchild := newTree(t, ecs)
+	for _, e := range []entity.Entity{parent, child, grandchild} {
+		if _, err := ecsys.Add(ecs, *velocity.New(e, point.Zero())); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := ecs.SetActive(child, false); err != nil {
+		t.Fatalf("SetActive() error = %v", err)
+	}
+
+	t.Run("queries should skip inactive entities", func(t *testing.T) {
+		rows := ecsys.Query2[velocity.Velocity, position.Position](ecs)
+		if len(rows) != 1 || rows[0].Entity != parent {
+			t.Errorf("Query2() = %v, want only %v", rows, parent)
+		}
+		rows = ecsys.Query2[velocity.Velocity, position.Position](ecs, ecsys.IncludeInactive())
+		if len(rows) != 3 {
+			t.Errorf("Query2(IncludeInactive) len = %d, want 3", len(rows))
+		}
+	})
+
+	t.Run("iterators should skip inactive entities", func(t *testing.T) {
+		var got []entity.Entity
+		for e := range ecsys.Iter[position.Position](ecs) {
+			got = append(got, e)
+		}
+		if diff := cmp.Diff([]entity.Entity{parent}, got); diff != "" {
+			t.Errorf("Iter() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("All should skip inactive entities", func(t *testing.T) {
+		if l := len(ecsys.All[velocity.Velocity](ecs)); l != 1 {
+			t.Errorf("All() len = %d, want 1", l)
+		}
+		if l := len(ecsys.All[position.Position](ecs, ecsys.IncludeInactive())); l != 3 {
+			t.Errorf("All(IncludeInactive) len = %d, want 3", l)
+		}
+	})
+
+	t.Run("Get should still return components of inactive entities", func(t *testing.T) {
+		if _, err := ecsys.Get[velocity.Velocity](ecs, grandchild); err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	})
+}
+
+func TestECS_Active_Concurrent(t *testing.T) {
+	ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+	parent, child, grandchild := newTree(t, ecs)
+	if err := ecs.SetActive(child, false); err != nil {
+		t.Fatalf("SetActive() error = %v", err)
+	}
+	want := map[entity.Entity]bool{parent: true, child: false, grandchild: false}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				for e, a := range want {
+					if got := ecs.Active(e); got != a {
+						t.Errorf("Active(%v) = %v, want %v", e, got, a)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestECS_Active_WhileIterating(t *testing.T) {
+	ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+	_, child, grandchild := newTree(t, ecs)
+	if err := ecs.SetActive(grandchild, true); err != nil {
+		t.Fatalf("SetActive() error = %v", err)
+	}
+
+	// Iterating looks up active states while the store is read-locked, other goroutines
+	// change active components meanwhile.
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			if err := ecs.SetActive(child, i%2 == 0); err != nil {
+				t.Errorf("SetActive() error = %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			for range ecsys.Iter[active.Active](ecs, ecsys.IncludeInactive()) {
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			for range ecsys.IterQuery1[position.Position](ecs) {
+			}
+		}
+	}()
+	wg.Wait()
+
+	if err := ecs.SetActive(child, false); err != nil {
+		t.Fatalf("SetActive() error = %v", err)
+	}
+	if ecs.Active(grandchild) {
+		t.Errorf("Active(%v) = true, want false", grandchild)
+	}
+}
@@ -1,89 +1,65 @@
 package ecsys
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dwethmar/vork/component"
-	"github.com/dwethmar/vork/component/controllable"
-	"github.com/dwethmar/vork/component/hitbox"
-	"github.com/dwethmar/vork/component/position"
-	"github.com/dwethmar/vork/component/shape"
-	"github.com/dwethmar/vork/component/skeleton"
-	"github.com/dwethmar/vork/component/sprite"
-	"github.com/dwethmar/vork/component/velocity"
-	"github.com/dwethmar/vork/event"
 )
 
-// addComponent adds a component to the ECS. It returns the ID of the component.
-func addComponent[T component.Component](
-	ecs *ECS,
-	c component.Component,
-	store Store[T],
-	eventCreator func(T) event.Event,
-) (uint, error) {
-	comp, ok := c.(T)
-	if !ok {
-		return 0, fmt.Errorf("expected component of type %T, got %T", comp, c)
+// Add adds a component of a registered type to the ECS. It returns the ID of the component.
+func Add[C any, T ComponentPointer[C]](s *ECS, c C) (uint, error) {
+	r, err := lookup[T]()
+	if err != nil {
+		return 0, err
 	}
-	id, err := store.Add(comp)
+	return addComponent(s, r, T(&c))
+}
+
+// addComponent adds a component to the ECS. It returns the ID of the component.
+func addComponent[T component.Component](ecs *ECS, r Registration[T], comp T) (uint, error) {
+	store, err := StoreFor[T](ecs.stores)
 	if err != nil {
+		return 0, err
+	}
+	// Track the entity if it is not known yet, a deleted entity cannot get new components.
+	if err = ecs.track(comp.Entity()); err != nil {
 		return 0, fmt.Errorf("could not add component of type %T: %w", comp, err)
 	}
-	if eventCreator != nil {
-		if err = ecs.eventBus.Publish(eventCreator(comp)); err != nil {
-			return 0, fmt.Errorf("could not publish add event: %w", err)
+	owners := ecs.owners.of(r.Type)
+	id, err := owners.write(ecs.staging, func() (uint, error) {
+		id, err := store.Add(comp)
+		if err != nil {
+			return 0, fmt.Errorf("could not add component of type %T: %w", comp, err)
 		}
-	}
-	// Update the lastEntityID if the entity is higher than the current lastEntityID.
-	if c.Entity() > ecs.lastEntityID {
-		ecs.lastEntityID = c.Entity()
-	}
-	return id, nil
-}
-
-// AddPosition adds a position component to the ECS.
-func (s *ECS) AddPosition(c position.Position) (uint, error) {
-	id, err := addComponent(s, &c, s.stores.Position, func(p *position.Position) event.Event {
-		return position.NewCreatedEvent(*p)
+		if err = ecs.indexes.add(r.Type, comp); err != nil {
+			if dErr := store.Delete(id); dErr != nil {
+				return 0, errors.Join(err, dErr)
+			}
+			return 0, fmt.Errorf("could not add component of type %T: %w", comp, err)
+		}
+		return id, nil
 	})
 	if err != nil {
-		return 0, fmt.Errorf("could not add position component: %w", err)
+		return 0, err
 	}
-	// Add the entity to the hierarchy.
-	if err = s.hierarchy.Add(c.Parent, c.Entity()); err != nil {
-		return 0, fmt.Errorf("could not add entity to hierarchy: %w", err)
+	ecs.recordComponentUndo(r.Type, id, func() error {
+		ecs.indexes.remove(r.Type, id)
+		return store.Delete(id)
+	})
+	ecs.recordComponentUndo(r.Type, id, ecs.changes.touch(r.Type, id))
+	if r.CreatedEvent != nil {
+		if err = ecs.publish(r.CreatedEvent(comp)); err != nil {
+			return 0, fmt.Errorf("could not publish add event: %w", err)
+		}
+	}
+	if r.AfterAdd != nil {
+		if err = r.AfterAdd(ecs, comp); err != nil {
+			return 0, err
+		}
+	}
+	if err = ecs.notifyAdd(r.Type, r.clone(comp)); err != nil {
+		return 0, err
 	}
 	return id, nil
 }
-
-func (s *ECS) AddVelocity(c velocity.Velocity) (uint, error) {
-	return addComponent(s, &c, s.stores.Velocity, func(v *velocity.Velocity) event.Event {
-		return velocity.NewCreatedEvent(*v)
-	})
-}
-
-func (s *ECS) AddHitbox(c hitbox.Hitbox) (uint, error) {
-	return addComponent(s, &c, s.stores.Hitbox, func(h *hitbox.Hitbox) event.Event {
-		return hitbox.NewCreatedEvent(*h)
-	})
-}
-
-func (s *ECS) AddControllable(c controllable.Controllable) (uint, error) {
-	return addComponent(s, &c, s.stores.Controllable, func(ctr *controllable.Controllable) event.Event {
-		return controllable.NewCreatedEvent(*ctr)
-	})
-}
-
-func (s *ECS) AddRectangle(c shape.Rectangle) (uint, error) {
-	return addComponent(s, &c, s.stores.Rectangle, nil)
-}
-
-func (s *ECS) AddSprite(c sprite.Sprite) (uint, error) {
-	return addComponent(s, &c, s.stores.Sprite, nil)
-}
-
-func (s *ECS) AddSkeleton(c skeleton.Skeleton) (uint, error) {
-	return addComponent(s, &c, s.stores.Skeleton, func(sk *skeleton.Skeleton) event.Event {
-		return skeleton.NewCreatedEvent(*sk)
-	})
-}
@@ -1,8 +1,10 @@
 package ecsys_test
 
 import (
+	"image/color"
 	"testing"
 
+	"github.com/dwethmar/vork/component/shape"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
@@ -10,7 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
-func TestECS_AddPositionComponent(t *testing.T) {
+func TestAdd_Position(t *testing.T) {
 	t.Run("should add a position component", func(t *testing.T) {
 		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
 		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
@@ -24,3 +26,56 @@ func TestECS_AddPositionComponent(t *testing.T) {
 		}
 	})
 }
+
+func TestAdd_Shapes(t *testing.T) {
+	t.Run("should add every kind of shape", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		red := color.NRGBA{R: 0xff, A: 0xff}
+		if _, err = ecsys.Add(ecs, *shape.NewRectangle(e, 10, 10, red)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		for range 2 {
+			if _, err = ecsys.Add(ecs, *shape.NewCircle(e, 5, red)); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+		if _, err = ecsys.Add(ecs, *shape.NewLine(e, point.New(10, 0), red, 2)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *shape.NewPolygon(e, red, point.Zero(), point.New(5, 0), point.New(0, 5))); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		got := []int{len(ecsys.List[shape.Rectangle](ecs, e)), len(ecsys.List[shape.Circle](ecs, e)), len(ecsys.List[shape.Line](ecs, e)), len(ecsys.List[shape.Polygon](ecs, e))}
+		if diff := cmp.Diff([]int{1, 2, 1, 1}, got); diff != "" {
+			t.Errorf("shapes mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("snapshots should not share the points of a polygon", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		p := shape.NewPolygon(e, color.NRGBA{A: 0xff}, point.Zero(), point.New(5, 0), point.New(0, 5))
+		if p.I, err = ecsys.Add(ecs, *p); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		snap := ecs.Snapshot()
+		p.Points = []point.Point{point.New(1, 1)}
+		if err = ecsys.Update(ecs, *p); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if err = ecs.Restore(snap); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if got := ecsys.List[shape.Polygon](ecs, e); len(got) != 1 || len(got[0].Points) != 3 {
+			t.Errorf("List() = %v, want the polygon with 3 points", got)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+// componentKey identifies a component across all stores.
+type componentKey struct {
+	t  component.Type
+	id uint
+}
+
+// changes keeps track of the components that changed, per tick.
+// A tick is a frame, it is advanced by EndFrame.
+type changes struct {
+	mu      sync.Mutex
+	tick    uint64
+	dirty   []componentKey                     // components marked dirty this frame, in the order they were marked
+	isDirty map[componentKey]bool              // set of dirty components
+	changed map[component.Type]map[uint]uint64 // tick a component last changed, per type
+}
+
+func newChanges() *changes {
+	return &changes{
+		isDirty: make(map[componentKey]bool),
+		changed: make(map[component.Type]map[uint]uint64),
+	}
+}
+
+// reset forgets all changes, the tick is kept.
+func (c *changes) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dirty = nil
+	clear(c.isDirty)
+	clear(c.changed)
+}
+
+// touch records that the component changed in the current tick.
+// It returns a function that undoes the change, see recordUndo.
+func (c *changes) touch(t component.Type, id uint) func() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	undo := c.restorerLocked(t, id)
+	c.touchLocked(t, id)
+	return undo
+}
+
+// restorerLocked returns a function that restores the current changes of the component.
+func (c *changes) restorerLocked(t component.Type, id uint) func() error {
+	k := componentKey{t: t, id: id}
+	tick, changed := c.changed[t][id]
+	dirty := c.isDirty[k]
+	return func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if changed {
+			c.touchLocked(t, id)
+			c.changed[t][id] = tick
+		} else {
+			delete(c.changed[t], id)
+		}
+		if dirty {
+			c.isDirty[k] = true // the key is still in the dirty list, endFrame skips keys that are not dirty
+		} else {
+			delete(c.isDirty, k)
+		}
+		return nil
+	}
+}
+
+func (c *changes) touchLocked(t component.Type, id uint) {
+	m, ok := c.changed[t]
+	if !ok {
+		m = make(map[uint]uint64)
+		c.changed[t] = m
+	}
+	m[id] = c.tick
+}
+
+// markDirty records that the component changed and that its change event has to be published at the end of the frame.
+// It returns a function that undoes the change, see recordUndo.
+func (c *changes) markDirty(t component.Type, id uint) func() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	undo := c.restorerLocked(t, id)
+	c.touchLocked(t, id)
+	k := componentKey{t: t, id: id}
+	if !c.isDirty[k] {
+		c.isDirty[k] = true
+		c.dirty = append(c.dirty, k)
+	}
+	return undo
+}
+
+// forget drops a deleted component.
+// It returns a function that undoes the change, see recordUndo.
+func (c *changes) forget(t component.Type, id uint) func() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	undo := c.restorerLocked(t, id)
+	delete(c.changed[t], id)
+	delete(c.isDirty, componentKey{t: t, id: id})
+	return undo
+}
+
+// since returns the IDs of the components of the type that changed at or after the tick, in ID order.
+func (c *changes) since(t component.Type, tick uint64) []uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ids []uint
+	for id, changed := range c.changed[t] {
+		if changed >= tick {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// endFrame returns the dirty components, clears them and advances the tick.
+func (c *changes) endFrame() []componentKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// a key can be in the list more than once if it was forgotten or rolled back and marked again
+	dirty := slices.DeleteFunc(c.dirty, func(k componentKey) bool {
+		d := c.isDirty[k]
+		delete(c.isDirty, k)
+		return !d
+	})
+	c.dirty = nil
+	clear(c.isDirty)
+	c.tick++
+	return dirty
+}
+
+// Tick returns the current tick. The tick is advanced by EndFrame.
+func (s *ECS) Tick() uint64 {
+	s.changes.mu.Lock()
+	defer s.changes.mu.Unlock()
+	return s.changes.tick
+}
+
+// EndFrame publishes one update event for every component that was marked dirty during the frame,
+// with the state of the component at the end of the frame, and advances the tick.
+// Components that were deleted after being marked dirty are skipped.
+func (s *ECS) EndFrame() error {
+	var errs []error
+	for _, k := range s.changes.endFrame() {
+		registry.mu.RLock()
+		r, ok := registry.byType[k.t]
+		registry.mu.RUnlock()
+		if !ok {
+			continue
+		}
+		if err := r.publishUpdated(s, k.id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("could not publish change events: %w", err)
+	}
+	return nil
+}
+
+// Mutate borrows the first component of type C of the entity for in-place mutation.
+// If fn reports that it changed the component, the component is written back and marked dirty,
+// see MarkDirty.
+func Mutate[C any, T ComponentPointer[C]](s *ECS, e entity.Entity, fn func(T) bool) error {
+	c, err := Get[C, T](s, e)
+	if err != nil {
+		return err
+	}
+	if !fn(&c) {
+		return nil
+	}
+	return MarkDirty[C, T](s, c)
+}
+
+// MarkDirty writes back a changed component and marks it dirty. Unlike Update, no event is published
+// right away. At most one update event per component is published at the end of the frame by EndFrame.
+func MarkDirty[C any, T ComponentPointer[C]](s *ECS, c C) error {
+	r, err := lookup[T]()
+	if err != nil {
+		return err
+	}
+	return writeComponent(s, r, T(&c), false)
+}
+
+// ChangedSince returns the components of type C that were added or changed at or after the tick.
+func ChangedSince[C any, T ComponentPointer[C]](s *ECS, tick uint64) []C {
+	store, err := StoreFor[T](s.stores)
+	if err != nil {
+		return nil
+	}
+	var r []C
+	for _, id := range s.changes.since(T(new(C)).Type(), tick) {
+		if c, err := store.Get(id); err == nil {
+			r = append(r, *c)
+		}
+	}
+	return r
+}
+
+// publishUpdated publishes the update event of the component with the given ID if it still exists.
+func (r Registration[T]) publishUpdated(s *ECS, id uint) error {
+	if r.UpdatedEvent == nil {
+		return nil
+	}
+	store, err := StoreFor[T](s.stores)
+	if err != nil {
+		return err
+	}
+	c, err := store.Get(id)
+	if errors.Is(err, ErrComponentNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return s.publish(r.UpdatedEvent(c))
+}
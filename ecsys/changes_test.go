@@ -0,0 +1,208 @@
+package ecsys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestECS_EndFrame(t *testing.T) {
+	t.Run("should publish one coalesced event per dirty component", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+
+		var published []position.Position
+		bus.Subscribe(event.MatchAny(position.UpdatedEventType), func(ev event.Event) error {
+			if pe, ok := ev.(position.Event); ok {
+				published = append(published, *pe.Position())
+			}
+			return nil
+		})
+
+		for range 3 {
+			if err = ecsys.Mutate(ecs, e, func(p *position.Position) bool {
+				p.X++
+				return true
+			}); err != nil {
+				t.Fatalf("Mutate() error = %v", err)
+			}
+		}
+		// Not changed, so not marked dirty.
+		if err = ecsys.Mutate(ecs, e, func(*position.Position) bool { return false }); err != nil {
+			t.Fatalf("Mutate() error = %v", err)
+		}
+		if len(published) != 0 {
+			t.Fatalf("no events should be published before EndFrame(), got %d", len(published))
+		}
+		if p, _ := ecsys.Get[position.Position](ecs, e); p.X != 3 {
+			t.Errorf("Get().X = %d, want 3", p.X)
+		}
+
+		if err = ecs.EndFrame(); err != nil {
+			t.Fatalf("EndFrame() error = %v", err)
+		}
+		if len(published) != 1 {
+			t.Fatalf("published %d events, want 1", len(published))
+		}
+		if published[0].X != 3 {
+			t.Errorf("published X = %d, want 3", published[0].X)
+		}
+
+		// Nothing is dirty in the next frame.
+		if err = ecs.EndFrame(); err != nil {
+			t.Fatalf("EndFrame() error = %v", err)
+		}
+		if len(published) != 1 {
+			t.Errorf("published %d events, want 1", len(published))
+		}
+	})
+
+	t.Run("should skip components deleted after being marked dirty", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *velocity.New(e, point.Zero())); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		vel, err := ecsys.Get[velocity.Velocity](ecs, e)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		vel.X = 1
+		if err = ecsys.MarkDirty(ecs, vel); err != nil {
+			t.Fatalf("MarkDirty() error = %v", err)
+		}
+		if err = ecsys.Delete(ecs, vel); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		published := 0
+		bus.Subscribe(event.MatchAny(velocity.UpdatedEventType), func(event.Event) error {
+			published++
+			return nil
+		})
+		if err = ecs.EndFrame(); err != nil {
+			t.Fatalf("EndFrame() error = %v", err)
+		}
+		if published != 0 {
+			t.Errorf("published %d events, want 0", published)
+		}
+	})
+}
+
+func TestChangedSince(t *testing.T) {
+	t.Run("should return the components changed at or after a tick", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		a, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		b, err := ecs.CreateEntity(ecs.Root(), point.New(2, 2))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if err = ecs.EndFrame(); err != nil {
+			t.Fatalf("EndFrame() error = %v", err)
+		}
+
+		tick := ecs.Tick()
+		if tick != 1 {
+			t.Errorf("Tick() = %d, want 1", tick)
+		}
+		if got := ecsys.ChangedSince[position.Position](ecs, tick); len(got) != 0 {
+			t.Errorf("ChangedSince() = %v, want none", got)
+		}
+		if err = ecsys.Mutate(ecs, b, func(p *position.Position) bool {
+			p.Y = 5
+			return true
+		}); err != nil {
+			t.Fatalf("Mutate() error = %v", err)
+		}
+
+		got := ecsys.ChangedSince[position.Position](ecs, tick)
+		pb, _ := ecsys.Get[position.Position](ecs, b)
+		if diff := cmp.Diff([]position.Position{pb}, got); diff != "" {
+			t.Errorf("ChangedSince() mismatch (-want +got):\n%s", diff)
+		}
+		if got = ecsys.ChangedSince[position.Position](ecs, 0); len(got) != 2 {
+			t.Errorf("ChangedSince(0) len = %d, want 2", len(got))
+		}
+		if err = ecs.DeleteEntity(a); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if got = ecsys.ChangedSince[position.Position](ecs, 0); len(got) != 1 {
+			t.Errorf("ChangedSince(0) after delete len = %d, want 1", len(got))
+		}
+	})
+}
+
+func TestECS_RollbackChanges(t *testing.T) {
+	bus := event.NewBus()
+	ecs := ecsys.New(bus, ecsys.NewStores())
+	e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if err = ecs.EndFrame(); err != nil {
+		t.Fatalf("EndFrame() error = %v", err)
+	}
+	tick := ecs.Tick()
+	published := 0
+	bus.Subscribe(event.MatchAny(position.UpdatedEventType, velocity.UpdatedEventType), func(event.Event) error {
+		published++
+		return nil
+	})
+
+	errRollback := errors.New("roll back")
+	err = ecs.Tx(func(tx *ecsys.ECS) error {
+		if err := ecsys.Mutate(tx, e, func(p *position.Position) bool {
+			p.X = 5
+			return true
+		}); err != nil {
+			return err
+		}
+		if _, err := ecsys.Add(tx, *velocity.New(e, point.Zero())); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("Tx() error = %v, want %v", err, errRollback)
+	}
+
+	if got := ecsys.ChangedSince[position.Position](ecs, tick); len(got) != 0 {
+		t.Errorf("ChangedSince() = %v, want none", got)
+	}
+	if err = ecs.EndFrame(); err != nil {
+		t.Fatalf("EndFrame() error = %v", err)
+	}
+	if published != 0 {
+		t.Errorf("published %d events, want 0", published)
+	}
+
+	// A component marked dirty again after a rollback is published once.
+	if err = ecsys.Mutate(ecs, e, func(p *position.Position) bool {
+		p.X = 6
+		return true
+	}); err != nil {
+		t.Fatalf("Mutate() error = %v", err)
+	}
+	if err = ecs.EndFrame(); err != nil {
+		t.Fatalf("EndFrame() error = %v", err)
+	}
+	if published != 1 {
+		t.Errorf("published %d events, want 1", published)
+	}
+}
@@ -0,0 +1,172 @@
+package ecsys
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/point"
+)
+
+// command is a structural change recorded in a command buffer.
+type command struct {
+	name  string
+	apply func(s *ECS) error
+}
+
+// CommandBuffer records structural changes to the ECS, like creating and deleting entities and
+// adding, updating and deleting components, so they can be applied later at a defined sync point.
+// Systems record into a buffer while they iterate over components and event handlers record into
+// a buffer instead of changing the ECS while an event is being published.
+//
+// Components are recorded as pointers and must not be modified after they have been recorded.
+// A CommandBuffer is safe for concurrent use.
+type CommandBuffer struct {
+	mu       sync.Mutex
+	ecs      *ECS
+	commands []command
+	created  []entity.Entity // entities reserved by CreateEntity
+}
+
+// NewCommandBuffer creates a command buffer that applies its commands to the ECS.
+func NewCommandBuffer(s *ECS) *CommandBuffer {
+	return &CommandBuffer{
+		ecs: s,
+	}
+}
+
+// record appends a command to the buffer.
+func (b *CommandBuffer) record(name string, apply func(s *ECS) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commands = append(b.commands, command{name: name, apply: apply})
+}
+
+// Len returns the number of recorded commands.
+func (b *CommandBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.commands)
+}
+
+// CreateEntity records the creation of an entity with a position component.
+// The entity is reserved right away so later commands in the buffer can refer to it, its created
+// event is published when the buffer is applied. It is deleted again if the buffer is reset or fails to apply.
+func (b *CommandBuffer) CreateEntity(parent entity.Entity, p point.Point) entity.Entity {
+	e := b.ecs.reserve()
+	b.mu.Lock()
+	b.created = append(b.created, e)
+	b.mu.Unlock()
+	b.record("create entity", func(s *ECS) error {
+		if err := s.publish(entity.NewCreatedEvent(e)); err != nil {
+			return fmt.Errorf("could not publish create event: %w", err)
+		}
+		_, err := Add(s, *position.New(parent, e, p))
+		return err
+	})
+	return e
+}
+
+// DeleteEntity records the deletion of an entity and all its components.
+func (b *CommandBuffer) DeleteEntity(e entity.Entity) {
+	b.record("delete entity", func(s *ECS) error {
+		return s.DeleteEntity(e)
+	})
+}
+
+// Add records adding a component of a registered type.
+func (b *CommandBuffer) Add(c component.Component) {
+	b.record("add "+string(c.Type()), func(s *ECS) error {
+		r, err := lookupComponent(c)
+		if err != nil {
+			return err
+		}
+		return r.addAny(s, c)
+	})
+}
+
+// Update records updating a component of a registered type.
+func (b *CommandBuffer) Update(c component.Component) {
+	b.record("update "+string(c.Type()), func(s *ECS) error {
+		r, err := lookupComponent(c)
+		if err != nil {
+			return err
+		}
+		return r.updateAny(s, c)
+	})
+}
+
+// Delete records deleting a component of a registered type.
+func (b *CommandBuffer) Delete(c component.Component) {
+	b.record("delete "+string(c.Type()), func(s *ECS) error {
+		r, err := lookupComponent(c)
+		if err != nil {
+			return err
+		}
+		return r.deleteAny(s, c)
+	})
+}
+
+// Reparent records moving an entity, and with it its descendants, to a new parent.
+// The position of the entity stays relative to its parent.
+func (b *CommandBuffer) Reparent(e, parent entity.Entity) {
+	b.record("reparent", func(s *ECS) error {
+		pos, err := Get[position.Position](s, e)
+		if err != nil {
+			return err
+		}
+		pos.Parent = parent
+		return Update(s, pos)
+	})
+}
+
+// Reset drops all recorded commands and deletes the entities reserved by CreateEntity.
+func (b *CommandBuffer) Reset() {
+	_, created := b.take()
+	b.release(created)
+}
+
+// take empties the buffer and returns its commands and reserved entities.
+func (b *CommandBuffer) take() ([]command, []entity.Entity) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	commands, created := b.commands, b.created
+	b.commands, b.created = nil, nil
+	return commands, created
+}
+
+// release deletes reserved entities that have not been created.
+func (b *CommandBuffer) release(created []entity.Entity) {
+	b.ecs.mu.Lock()
+	defer b.ecs.mu.Unlock()
+	for _, e := range created {
+		b.ecs.entities.destroy(e)
+	}
+}
+
+// Apply applies the recorded commands in the order they were recorded and empties the buffer.
+// Events are published after all commands have been applied. If a command fails, all changes
+// made by the buffer are rolled back, no events are published and the reserved entities are deleted.
+// Changes made by event handlers are not part of the buffer and are not rolled back.
+func (b *CommandBuffer) Apply() error {
+	commands, created := b.take()
+	if len(commands) == 0 {
+		return nil
+	}
+	applied := false
+	err := b.ecs.Tx(func(tx *ECS) error {
+		for i, c := range commands {
+			if err := c.apply(tx); err != nil {
+				return fmt.Errorf("could not apply command %d (%s): %w", i, c.name, err)
+			}
+		}
+		applied = true
+		return nil
+	})
+	if !applied {
+		b.release(created)
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package ecsys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCommandBuffer_Apply(t *testing.T) {
+	t.Run("should apply commands in order and publish events afterwards", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+
+		var published []string
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(e event.Event) error {
+			published = append(published, e.Event())
+			return nil
+		})
+
+		b := ecsys.NewCommandBuffer(ecs)
+		e := b.CreateEntity(ecs.Root(), point.New(2, 2))
+		b.Add(velocity.New(e, point.New(1, 0)))
+		b.Reparent(e, parent)
+		if l := b.Len(); l != 3 {
+			t.Errorf("Len() = %d, want 3", l)
+		}
+		if len(published) != 0 {
+			t.Errorf("no events should be published before Apply, got %v", published)
+		}
+
+		if err = b.Apply(); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		want := []string{entity.CreatedEventType, position.CreatedEventType, velocity.CreatedEventType, position.UpdatedEventType}
+		if diff := cmp.Diff(want, published); diff != "" {
+			t.Errorf("published mismatch (-want +got):\n%s", diff)
+		}
+		if p, _ := ecs.Parent(e); p != parent {
+			t.Errorf("Parent() = %v, want %v", p, parent)
+		}
+		if p, _ := ecs.GetAbsolutePosition(e); p != point.New(3, 3) {
+			t.Errorf("GetAbsolutePosition() = %v, want (3, 3)", p)
+		}
+		if l := b.Len(); l != 0 {
+			t.Errorf("Len() after Apply() = %d, want 0", l)
+		}
+	})
+
+	t.Run("should roll back all changes if a command fails", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(e, point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		pos, err := ecsys.Get[position.Position](ecs, e)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+
+		published := 0
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(event.Event) error {
+			published++
+			return nil
+		})
+
+		b := ecsys.NewCommandBuffer(ecs)
+		created := b.CreateEntity(ecs.Root(), point.Zero())
+		pos.X = 10
+		b.Update(&pos)
+		b.DeleteEntity(e)
+		b.Add(&UnregisteredComponent{E: e})
+
+		if err = b.Apply(); !errors.Is(err, ecsys.ErrComponentNotRegistered) {
+			t.Fatalf("Apply() should return ErrComponentNotRegistered, got %v", err)
+		}
+		if published != 0 {
+			t.Errorf("no events should be published, got %d", published)
+		}
+		if ecs.Alive(created) {
+			t.Error("created entity should not be alive")
+		}
+		if !ecs.Alive(e) || !ecs.Alive(child) {
+			t.Error("deleted entities should be alive again")
+		}
+		got, err := ecsys.Get[position.Position](ecs, e)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.X != 1 {
+			t.Errorf("Get().X = %d, want 1", got.X)
+		}
+		if p, err := ecs.Parent(child); err != nil || p != e {
+			t.Errorf("Parent() = %v, %v, want %v", p, err, e)
+		}
+		if l := len(ecsys.All[position.Position](ecs)); l != 2 {
+			t.Errorf("All() len = %d, want 2", l)
+		}
+	})
+
+	t.Run("should delete reserved entities on reset", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		b := ecsys.NewCommandBuffer(ecs)
+		e := b.CreateEntity(ecs.Root(), point.Zero())
+		if !ecs.Alive(e) {
+			t.Error("reserved entity should be alive")
+		}
+		b.Reset()
+		if ecs.Alive(e) {
+			t.Error("reserved entity should not be alive after Reset()")
+		}
+		if err := b.Apply(); err != nil {
+			t.Errorf("Apply() error = %v", err)
+		}
+		if _, err := ecsys.Get[position.Position](ecs, e); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("Get() should return ErrEntityNotFound, got %v", err)
+		}
+	})
+
+	t.Run("should let handlers record into a buffer while an event is published", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		b := ecsys.NewCommandBuffer(ecs)
+		bus.Subscribe(event.MatchAny(position.CreatedEventType), func(ev event.Event) error {
+			pe, ok := ev.(*position.CreatedEvent)
+			if !ok {
+				return nil
+			}
+			b.Add(velocity.New(pe.Position().Entity(), point.Zero()))
+			return nil
+		})
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecsys.Get[velocity.Velocity](ecs, e); err == nil {
+			t.Error("velocity should not be added before Apply()")
+		}
+		if err = b.Apply(); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if _, err = ecsys.Get[velocity.Velocity](ecs, e); err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dwethmar/vork/component/active"
+	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/name"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/relation"
+	"github.com/dwethmar/vork/component/shape"
+	"github.com/dwethmar/vork/component/skeleton"
+	"github.com/dwethmar/vork/component/sprite"
+	"github.com/dwethmar/vork/component/tags"
+	"github.com/dwethmar/vork/component/transform"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/hierarchy"
+)
+
+// init registers the component types that are built into the ECS.
+// Component packages outside of ecsys register their own types with Register.
+func init() {
+	Register(Registration[*position.Position]{
+		Type:            position.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(p *position.Position) event.Event { return position.NewCreatedEvent(*p) },
+		UpdatedEvent:    func(p *position.Position) event.Event { return position.NewUpdatedEvent(*p) },
+		DeletedEvent:    func(p *position.Position) event.Event { return position.NewDeletedEvent(*p) },
+		AfterAdd:        addToHierarchy,
+		AfterUpdate:     updateInHierarchy,
+		AfterDelete:     deleteFromHierarchy,
+	})
+	Register(Registration[*controllable.Controllable]{
+		Type:            controllable.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(c *controllable.Controllable) event.Event { return controllable.NewCreatedEvent(*c) },
+		UpdatedEvent:    func(c *controllable.Controllable) event.Event { return controllable.NewUpdatedEvent(*c) },
+		DeletedEvent:    func(c *controllable.Controllable) event.Event { return controllable.NewDeletedEvent(*c) },
+	})
+	Register(Registration[*velocity.Velocity]{
+		Type:            velocity.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(v *velocity.Velocity) event.Event { return velocity.NewCreatedEvent(*v) },
+		UpdatedEvent:    func(v *velocity.Velocity) event.Event { return velocity.NewUpdatedEvent(*v) },
+		DeletedEvent:    func(v *velocity.Velocity) event.Event { return velocity.NewDeletedEvent(*v) },
+	})
+	Register(Registration[*hitbox.Hitbox]{
+		Type:            hitbox.Type,
+		UniquePerEntity: false,
+		CreatedEvent:    func(h *hitbox.Hitbox) event.Event { return hitbox.NewCreatedEvent(*h) },
+		UpdatedEvent:    func(h *hitbox.Hitbox) event.Event { return hitbox.NewUpdatedEvent(*h) },
+		DeletedEvent:    func(h *hitbox.Hitbox) event.Event { return hitbox.NewDeletedEvent(*h) },
+		Indexes: []Index[*hitbox.Hitbox]{
+			{Name: hitbox.TagIndex, Values: func(h *hitbox.Hitbox) []string { return []string{h.Tag} }},
+		},
+	})
+	Register(Registration[*shape.Rectangle]{
+		Type:            shape.RectangleType,
+		UniquePerEntity: true,
+	})
+	Register(Registration[*shape.Circle]{
+		Type:            shape.CircleType,
+		UniquePerEntity: false,
+	})
+	Register(Registration[*shape.Line]{
+		Type:            shape.LineType,
+		UniquePerEntity: false,
+	})
+	Register(Registration[*shape.Polygon]{
+		Type:            shape.PolygonType,
+		UniquePerEntity: false,
+		Clone:           (*shape.Polygon).Clone,
+	})
+	Register(Registration[*sprite.Sprite]{
+		Type:            sprite.Type,
+		UniquePerEntity: false,
+		Indexes: []Index[*sprite.Sprite]{
+			{Name: sprite.TagIndex, Values: func(s *sprite.Sprite) []string { return []string{s.Tag} }},
+		},
+	})
+	Register(Registration[*skeleton.Skeleton]{
+		Type:            skeleton.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(sk *skeleton.Skeleton) event.Event { return skeleton.NewCreatedEvent(*sk) },
+		UpdatedEvent:    func(sk *skeleton.Skeleton) event.Event { return skeleton.NewUpdatedEvent(*sk) },
+		DeletedEvent:    func(sk *skeleton.Skeleton) event.Event { return skeleton.NewDeletedEvent(*sk) },
+	})
+	Register(Registration[*name.Name]{
+		Type:            name.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(n *name.Name) event.Event { return name.NewCreatedEvent(*n) },
+		UpdatedEvent:    func(n *name.Name) event.Event { return name.NewUpdatedEvent(*n) },
+		DeletedEvent:    func(n *name.Name) event.Event { return name.NewDeletedEvent(*n) },
+		Indexes: []Index[*name.Name]{
+			{Name: name.Index, Unique: true, Values: func(n *name.Name) []string { return []string{n.Name} }},
+		},
+	})
+	Register(Registration[*tags.Tags]{
+		Type:            tags.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(t *tags.Tags) event.Event { return tags.NewCreatedEvent(*t.Clone()) },
+		UpdatedEvent:    func(t *tags.Tags) event.Event { return tags.NewUpdatedEvent(*t.Clone()) },
+		DeletedEvent:    func(t *tags.Tags) event.Event { return tags.NewDeletedEvent(*t.Clone()) },
+		Clone:           (*tags.Tags).Clone,
+		Indexes: []Index[*tags.Tags]{
+			{Name: tags.Index, Values: func(t *tags.Tags) []string { return slices.Clone(t.Tags) }},
+		},
+	})
+	Register(Registration[*transform.Transform]{
+		Type:            transform.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(t *transform.Transform) event.Event { return transform.NewCreatedEvent(*t) },
+		UpdatedEvent:    func(t *transform.Transform) event.Event { return transform.NewUpdatedEvent(*t) },
+		DeletedEvent:    func(t *transform.Transform) event.Event { return transform.NewDeletedEvent(*t) },
+		AfterAdd:        invalidateEntityTransform,
+		AfterUpdate:     invalidateEntityTransform,
+		AfterDelete:     invalidateEntityTransform,
+	})
+	Register(Registration[*active.Active]{
+		Type:            active.Type,
+		UniquePerEntity: true,
+		Persistent:      true,
+		CreatedEvent:    func(a *active.Active) event.Event { return active.NewCreatedEvent(*a) },
+		UpdatedEvent:    func(a *active.Active) event.Event { return active.NewUpdatedEvent(*a) },
+		DeletedEvent:    func(a *active.Active) event.Event { return active.NewDeletedEvent(*a) },
+		AfterAdd:        invalidateEntityActive,
+		AfterUpdate:     invalidateEntityActive,
+		AfterDelete:     invalidateEntityActive,
+	})
+	Register(Registration[*relation.Relation]{
+		Type:            relation.Type,
+		UniquePerEntity: false,
+		Persistent:      true,
+		CreatedEvent:    func(r *relation.Relation) event.Event { return relation.NewCreatedEvent(*r) },
+		UpdatedEvent:    func(r *relation.Relation) event.Event { return relation.NewUpdatedEvent(*r) },
+		DeletedEvent:    func(r *relation.Relation) event.Event { return relation.NewDeletedEvent(*r) },
+		Indexes: []Index[*relation.Relation]{
+			{Name: relation.SourceIndex, Values: func(r *relation.Relation) []string { return []string{string(r.Kind)} }},
+			{Name: relation.TargetIndex, Values: func(r *relation.Relation) []string {
+				return []string{targetValue(r.Target), relation.TargetKey(r.Kind, r.Target)}
+			}},
+			{Name: relation.KeyIndex, Unique: true, Values: func(r *relation.Relation) []string { return []string{r.Key()} }},
+		},
+	})
+}
+
+// addToHierarchy adds the entity of the position to the hierarchy.
+func addToHierarchy(s *ECS, p *position.Position) error {
+	if err := s.hierarchy.Add(p.Parent, p.Entity()); err != nil {
+		return fmt.Errorf("could not add entity to hierarchy: %w", err)
+	}
+	s.invalidate(p.Entity())
+	s.recordComponentUndo(position.Type, p.ID(), func() error {
+		s.hierarchy.Delete(p.Entity())
+		return nil
+	})
+	return nil
+}
+
+// updateInHierarchy moves the entity of the position to its new parent.
+func updateInHierarchy(s *ECS, p *position.Position) error {
+	old, pErr := s.hierarchy.Parent(p.Entity())
+	if err := s.hierarchy.Update(p.Parent, p.Entity()); err != nil {
+		return fmt.Errorf("could not update entity in hierarchy: %w", err)
+	}
+	s.invalidate(p.Entity())
+	if pErr == nil && old != p.Parent {
+		s.recordComponentUndo(position.Type, p.ID(), func() error { return s.hierarchy.Update(old, p.Entity()) })
+	}
+	return nil
+}
+
+// deleteFromHierarchy removes the entity from the hierarchy and deletes the positions of its descendants.
+func deleteFromHierarchy(s *ECS, p *position.Position) error {
+	s.invalidate(p.Entity())
+	if s.isStaging() {
+		// The parents are collected top down, so the subtree can be added back in order.
+		var pairs []hierarchy.EntityPair
+		for _, e := range append([]entity.Entity{p.Entity()}, s.hierarchy.Descendants(p.Entity())...) {
+			if parent, err := s.hierarchy.Parent(e); err == nil {
+				pairs = append(pairs, hierarchy.EntityPair{Parent: parent, Child: e})
+			}
+		}
+		s.recordComponentUndo(position.Type, p.ID(), func() error {
+			var errs []error
+			for _, pair := range pairs {
+				errs = append(errs, s.hierarchy.Add(pair.Parent, pair.Child))
+			}
+			return errors.Join(errs...)
+		})
+	}
+	for _, descendant := range s.hierarchy.Delete(p.Entity()) {
+		if descendant == p.Entity() {
+			continue
+		}
+		pos, err := Get[position.Position](s, descendant)
+		if err != nil {
+			return fmt.Errorf("could not get position: %w", err)
+		}
+		if err = Delete(s, pos); err != nil {
+			return fmt.Errorf("could not delete position: %w", err)
+		}
+	}
+	return nil
+}
@@ -4,12 +4,13 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/dwethmar/vork/component/position"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/event"
 	"github.com/dwethmar/vork/point"
 )
 
-func TestECS_DeletePosition(t *testing.T) {
+func TestDelete_Position(t *testing.T) {
 	t.Run("should delete a position component", func(t *testing.T) {
 		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
 
@@ -24,13 +25,13 @@ func TestECS_DeletePosition(t *testing.T) {
 		}
 
 		// get position of child1
-		pos, err := ecs.GetPosition(child1)
+		pos, err := ecsys.Get[position.Position](ecs, child1)
 		if err != nil {
 			t.Errorf("Error getting position: %s", err)
 		}
 
 		// delete position
-		if err = ecs.DeletePosition(pos); err != nil {
+		if err = ecsys.Delete(ecs, pos); err != nil {
 			t.Errorf("Error deleting position: %s", err)
 		}
 
@@ -39,7 +40,7 @@ func TestECS_DeletePosition(t *testing.T) {
 			t.Errorf("Expected 0 children, got children %v", c)
 		}
 
-		_, err = ecs.GetPosition(child2)
+		_, err = ecsys.Get[position.Position](ecs, child2)
 		if !errors.Is(err, ecsys.ErrEntityNotFound) {
 			t.Errorf("Expected error, got %s", err)
 		}
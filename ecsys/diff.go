@@ -0,0 +1,218 @@
+package ecsys
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/entity"
+)
+
+// WorldDiff holds the differences between two snapshots, per entity.
+type WorldDiff struct {
+	Entities []EntityDiff // Entities that changed, ordered by entity.
+}
+
+// EntityDiff holds the differences of a single entity.
+type EntityDiff struct {
+	Entity     entity.Entity
+	Created    bool                  // The entity is only alive in the new snapshot.
+	Deleted    bool                  // The entity is only alive in the old snapshot.
+	Reparented *Reparent             // The parent of the entity changed, nil if it did not.
+	Added      []component.Component // Components only in the new snapshot.
+	Removed    []component.Component // Components only in the old snapshot.
+	Modified   []ComponentDiff       // Components in both snapshots with different fields.
+}
+
+// Reparent describes the change of the parent of an entity.
+type Reparent struct {
+	From, To entity.Entity
+}
+
+// ComponentDiff holds the fields of a component that differ between two snapshots.
+type ComponentDiff struct {
+	Type   component.Type
+	ID     uint
+	Fields []FieldDiff
+}
+
+// FieldDiff holds the old and new value of a field. Fields of nested structs
+// are named by their path, for example "Point.X".
+type FieldDiff struct {
+	Field    string
+	Old, New any
+}
+
+// componentRef identifies a component in a snapshot.
+type componentRef struct {
+	t  component.Type
+	id uint
+}
+
+// Diff compares two snapshots and reports which entities were created or deleted,
+// which components were added, removed or modified and which entities were reparented.
+// Components are matched by their type and ID.
+func Diff(before, after *Snapshot) WorldDiff {
+	diffs := map[entity.Entity]*EntityDiff{}
+	get := func(e entity.Entity) *EntityDiff {
+		d, ok := diffs[e]
+		if !ok {
+			d = &EntityDiff{Entity: e}
+			diffs[e] = d
+		}
+		return d
+	}
+
+	oldAlive, newAlive := before.entities.list(), after.entities.list()
+	for _, e := range oldAlive {
+		if !after.entities.isAlive(e) {
+			get(e).Deleted = true
+		}
+	}
+	for _, e := range newAlive {
+		if !before.entities.isAlive(e) {
+			get(e).Created = true
+		}
+	}
+
+	oldComps, newComps := before.index(), after.index()
+	for ref, o := range oldComps {
+		n, ok := newComps[ref]
+		if !ok {
+			d := get(o.Entity())
+			d.Removed = append(d.Removed, o)
+			continue
+		}
+		if o.Entity() != n.Entity() {
+			// The component moved to another entity.
+			get(o.Entity()).Removed = append(get(o.Entity()).Removed, o)
+			get(n.Entity()).Added = append(get(n.Entity()).Added, n)
+			continue
+		}
+		if fields := diffFields(o, n); len(fields) > 0 {
+			d := get(n.Entity())
+			d.Modified = append(d.Modified, ComponentDiff{Type: ref.t, ID: ref.id, Fields: fields})
+			if op, ok := o.(*position.Position); ok {
+				if np, ok := n.(*position.Position); ok && op.Parent != np.Parent {
+					d.Reparented = &Reparent{From: op.Parent, To: np.Parent}
+				}
+			}
+		}
+	}
+	for ref, n := range newComps {
+		if _, ok := oldComps[ref]; !ok {
+			d := get(n.Entity())
+			d.Added = append(d.Added, n)
+		}
+	}
+
+	w := WorldDiff{}
+	for _, d := range diffs {
+		byRef := func(a, b component.Component) int {
+			return cmp.Or(cmp.Compare(a.Type(), b.Type()), cmp.Compare(a.ID(), b.ID()))
+		}
+		slices.SortFunc(d.Added, byRef)
+		slices.SortFunc(d.Removed, byRef)
+		slices.SortFunc(d.Modified, func(a, b ComponentDiff) int {
+			return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+		})
+		w.Entities = append(w.Entities, *d)
+	}
+	slices.SortFunc(w.Entities, func(a, b EntityDiff) int { return cmp.Compare(a.Entity, b.Entity) })
+	return w
+}
+
+// Empty reports whether the snapshots are the same.
+func (d WorldDiff) Empty() bool {
+	return len(d.Entities) == 0
+}
+
+// Entity returns the differences of the entity, or false if it did not change.
+func (d WorldDiff) Entity(e entity.Entity) (EntityDiff, bool) {
+	for _, ed := range d.Entities {
+		if ed.Entity == e {
+			return ed, true
+		}
+	}
+	return EntityDiff{}, false
+}
+
+// String returns the differences in a human-readable form, one line per change.
+func (d WorldDiff) String() string {
+	if d.Empty() {
+		return "no differences\n"
+	}
+	var b strings.Builder
+	for _, ed := range d.Entities {
+		fmt.Fprintf(&b, "entity %v", ed.Entity)
+		switch {
+		case ed.Created:
+			b.WriteString(" (created)")
+		case ed.Deleted:
+			b.WriteString(" (deleted)")
+		}
+		b.WriteString("\n")
+		if ed.Reparented != nil {
+			fmt.Fprintf(&b, "  reparented: %v -> %v\n", ed.Reparented.From, ed.Reparented.To)
+		}
+		for _, c := range ed.Added {
+			fmt.Fprintf(&b, "  + %s#%d %+v\n", c.Type(), c.ID(), reflect.ValueOf(c).Elem().Interface())
+		}
+		for _, c := range ed.Removed {
+			fmt.Fprintf(&b, "  - %s#%d %+v\n", c.Type(), c.ID(), reflect.ValueOf(c).Elem().Interface())
+		}
+		for _, c := range ed.Modified {
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "  ~ %s#%d %s: %v -> %v\n", c.Type, c.ID, f.Field, f.Old, f.New)
+			}
+		}
+	}
+	return b.String()
+}
+
+// index returns the components of the snapshot by type and ID.
+func (s *Snapshot) index() map[componentRef]component.Component {
+	m := map[componentRef]component.Component{}
+	for t, comps := range s.components {
+		for _, c := range comps {
+			m[componentRef{t: t, id: c.ID()}] = c
+		}
+	}
+	return m
+}
+
+// diffFields returns the fields that differ between two components of the same type.
+func diffFields(before, after component.Component) []FieldDiff {
+	var diffs []FieldDiff
+	walkFields("", reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem(), &diffs)
+	return diffs
+}
+
+// walkFields compares two values of the same type, descending into structs.
+func walkFields(path string, before, after reflect.Value, diffs *[]FieldDiff) {
+	if before.Kind() == reflect.Struct {
+		for i := range before.NumField() {
+			f := before.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if path != "" {
+				name = path + "." + name
+			}
+			walkFields(name, before.Field(i), after.Field(i), diffs)
+		}
+		return
+	}
+	if !before.CanInterface() {
+		return
+	}
+	o, n := before.Interface(), after.Interface()
+	if !reflect.DeepEqual(o, n) {
+		*diffs = append(*diffs, FieldDiff{Field: path, Old: o, New: n})
+	}
+}
@@ -0,0 +1,110 @@
+package ecsys_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("should report no differences for the same world", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		if _, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1)); err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		d := ecsys.Diff(ecs.Snapshot(), ecs.Snapshot())
+		if !d.Empty() {
+			t.Errorf("Diff() should be empty, got:\n%s", d)
+		}
+		if d.String() != "no differences\n" {
+			t.Errorf("String() = %q", d.String())
+		}
+	})
+
+	t.Run("should report added, removed and modified components and reparenting", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		a, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		b, err := ecs.CreateEntity(ecs.Root(), point.New(2, 2))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		gone, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		before := ecs.Snapshot()
+
+		pos, _ := ecsys.Get[position.Position](ecs, b)
+		pos.Parent = a
+		pos.X = 5
+		if err = ecsys.Update(ecs, pos); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *velocity.New(a, point.New(1, 0))); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err = ecs.DeleteEntity(gone); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		created, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+
+		d := ecsys.Diff(before, ecs.Snapshot())
+		if l := len(d.Entities); l != 4 {
+			t.Fatalf("Diff() changed entities = %d, want 4:\n%s", l, d)
+		}
+
+		ad, _ := d.Entity(a)
+		if len(ad.Added) != 1 || ad.Added[0].Type() != velocity.Type {
+			t.Errorf("Added = %v, want a velocity", ad.Added)
+		}
+
+		bd, _ := d.Entity(b)
+		if diff := cmp.Diff(&ecsys.Reparent{From: ecs.Root(), To: a}, bd.Reparented); diff != "" {
+			t.Errorf("Reparented mismatch (-want +got):\n%s", diff)
+		}
+		wantFields := []ecsys.FieldDiff{
+			{Field: "Point.X", Old: 2, New: 5},
+			{Field: "Parent", Old: ecs.Root(), New: a},
+		}
+		if len(bd.Modified) != 1 || bd.Modified[0].Type != position.Type {
+			t.Fatalf("Modified = %v, want a position", bd.Modified)
+		}
+		if diff := cmp.Diff(wantFields, bd.Modified[0].Fields); diff != "" {
+			t.Errorf("Fields mismatch (-want +got):\n%s", diff)
+		}
+
+		gd, _ := d.Entity(gone)
+		if !gd.Deleted || len(gd.Removed) != 1 {
+			t.Errorf("Entity(gone) = %+v, want deleted with a removed position", gd)
+		}
+		cd, _ := d.Entity(created)
+		if !cd.Created || len(cd.Added) != 1 {
+			t.Errorf("Entity(created) = %+v, want created with an added position", cd)
+		}
+
+		text := d.String()
+		for _, want := range []string{
+			"reparented: 0 -> 1",
+			"~ position#2 Point.X: 2 -> 5",
+			"+ velocity#1",
+			"(deleted)",
+			"(created)",
+		} {
+			if !strings.Contains(text, want) {
+				t.Errorf("String() does not contain %q:\n%s", want, text)
+			}
+		}
+	})
+}
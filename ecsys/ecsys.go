@@ -6,65 +6,77 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/dwethmar/vork/component"
-	"github.com/dwethmar/vork/component/controllable"
 	"github.com/dwethmar/vork/component/position"
-	"github.com/dwethmar/vork/component/shape"
-	"github.com/dwethmar/vork/component/skeleton"
-	"github.com/dwethmar/vork/component/sprite"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
 	"github.com/dwethmar/vork/hierarchy"
 	"github.com/dwethmar/vork/point"
 )
 
-// componentTypes is a list of all component types used in the ECS.
-// This list is used to initialize the component stores in the ECS.
-// It is also used to ensure that all component types are accounted for when managing entities.
-func componentTypes() []component.Type {
-	return []component.Type{
-		position.Type,
-		controllable.Type,
-		shape.RectangleType,
-		sprite.Type,
-		skeleton.Type,
-	}
-}
-
 // ECS is the main struct that manages entities and their associated components.
-// It also provides access to various component stores (position, controllable, rectangle, sprite, skeleton)
+// It holds a store for every registered component type
 // and integrates an event bus for handling in-game events.
 type ECS struct {
+	*core
+	// staging records the changes of a transaction so they can be committed or rolled back as a whole.
+	// It is only set on the handle a transaction passes to its function, see Tx.
+	staging *staging
+}
+
+// core holds the state of the ECS, shared with the handles of its transactions.
+type core struct {
 	mu sync.RWMutex
-	// lastEntityID is the last entity ID that was created. It is used to generate new entity IDs.
-	// When adding a component with an entity ID higher than lastEntityID, lastEntityID is updated.
-	lastEntityID entity.Entity
-	eventBus     *event.Bus
-	stores       *Stores
-	hierarchy    *hierarchy.Hierarchy
+	// entities keeps track of the alive entities and their generation. It is used to generate new entity IDs.
+	// When adding a component for an entity that is not known yet, the entity is tracked as alive.
+	entities  *entities
+	eventBus  *event.Bus
+	stores    *Stores
+	hierarchy *hierarchy.Hierarchy
+	// changes keeps track of changed and dirty components per tick.
+	changes *changes
+	// resources holds the global resources, a single value per resource type.
+	resources *resources
+	// indexes holds the secondary indexes of the component types.
+	indexes *indexes
+	// worlds caches the world transforms of the entities.
+	worlds *worldTransforms
+	// observers holds the typed observers of component changes, see OnAdd, OnRemove and OnChange.
+	observers *observers
+	// activity caches whether the entities are effectively active.
+	activity *activity
+	// owners holds the transactions that last wrote the components, see Tx.
+	owners *owners
 }
 
 // New creates a new ECS system, initializing it with the provided component stores and event bus.
 // This function ensures that the ECS is ready to manage entities and components from the start.
 func New(eventBus *event.Bus, s *Stores) *ECS {
 	root := entity.Entity(0)
-	return &ECS{
-		lastEntityID: root,
-		eventBus:     eventBus,
-		stores:       s,
-		hierarchy:    hierarchy.New(root),
-	}
+	return &ECS{core: &core{
+		entities:  newEntities(root),
+		eventBus:  eventBus,
+		stores:    s,
+		hierarchy: hierarchy.New(root),
+		changes:   newChanges(),
+		resources: newResources(),
+		indexes:   newIndexes(s.types),
+		worlds:    newWorldTransforms(),
+		observers: newObservers(),
+		activity:  newActivity(),
+		owners:    newOwners(),
+	}}
 }
 
 func (s *ECS) BuildHierarchy() error {
 	// rebuild hierarchy
 	ep := []hierarchy.EntityPair{}
-	for _, p := range s.AllPositions() {
+	for e, p := range Iter[position.Position](s, IncludeInactive()) {
 		ep = append(ep, hierarchy.EntityPair{
 			Parent: p.Parent,
-			Child:  p.Entity(),
+			Child:  e,
 		})
 	}
+	defer s.resetCaches()
 	return s.hierarchy.Build(ep)
 }
 
@@ -83,85 +95,137 @@ func (s *ECS) Children(e entity.Entity) []entity.Entity {
 	return s.hierarchy.Children(e)
 }
 
-// CreateEntity generates a new unique entity by incrementing the lastEntityID.
+// CreateEntity generates a new unique entity.
 // It also creates a position component for the entity and adds it to the ECS.
 func (s *ECS) CreateEntity(parent entity.Entity, p point.Point) (entity.Entity, error) {
-	e := s.CreateEmptyEntity()
+	e, err := s.CreateEmptyEntity()
+	if err != nil {
+		return 0, err
+	}
 	pos := position.New(parent, e, p)
-	if _, err := s.AddPosition(*pos); err != nil {
+	if _, err := Add(s, *pos); err != nil {
 		return 0, err
 	}
 	return e, nil
 }
 
-// CreateEmptyEntity generates a new unique entity by incrementing the last entity ID.
-func (s *ECS) CreateEmptyEntity() entity.Entity {
+// CreateEmptyEntity generates a new unique entity and publishes a created event for it.
+// Indices of deleted entities are reused with a new generation.
+func (s *ECS) CreateEmptyEntity() (entity.Entity, error) {
+	e := s.reserve()
+	if err := s.publish(entity.NewCreatedEvent(e)); err != nil {
+		return 0, fmt.Errorf("could not publish create event: %w", err)
+	}
+	return e, nil
+}
+
+// RecreateEntity creates the entity with the index and generation it had when it was recorded,
+// for example when a journal is replayed, and publishes a created event for it.
+// It returns ErrEntityNotFound if the entity is stale.
+func (s *ECS) RecreateEntity(e entity.Entity) error {
+	if err := s.track(e); err != nil {
+		return err
+	}
+	if err := s.publish(entity.NewCreatedEvent(e)); err != nil {
+		return fmt.Errorf("could not publish create event: %w", err)
+	}
+	return nil
+}
+
+// reserve generates a new unique entity without publishing an event.
+func (s *ECS) reserve() entity.Entity {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.lastEntityID++
-	return s.lastEntityID
+	e := s.entities.create()
+	s.recordUndo(func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.entities.release(e)
+		return nil
+	})
+	return e
 }
 
-// DeleteEntity removes an entity and all its associated components from the ECS.
+// Alive reports whether the entity exists and has not been deleted.
+// It returns false for a handle to a deleted entity, even if its index has been reused.
+func (s *ECS) Alive(e entity.Entity) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.entities.isAlive(e)
+}
+
+// track marks the entity as alive if it is not known yet.
+// It returns ErrEntityNotFound if the entity has been deleted.
+func (s *ECS) track(e entity.Entity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	restore := s.entities.restorer(e.Index())
+	if !s.entities.track(e) {
+		return fmt.Errorf("entity %v is stale: %w", e, ErrEntityNotFound)
+	}
+	s.recordUndo(func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		restore()
+		return nil
+	})
+	return nil
+}
+
+// SyncEntities marks every entity that has a component in one of the stores as alive.
+// It is used after components have been loaded directly into the stores.
+func (s *ECS) SyncEntities() error {
+	for _, r := range s.stores.types {
+		for _, e := range r.entities(s.stores) {
+			if err := s.track(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteEntity removes an entity, all its associated components and the relations to it from the ECS.
+// A deleted event is published for the entity after the events of its components.
+// The index of the entity is reused by a later entity with a new generation.
 func (s *ECS) DeleteEntity(e entity.Entity) error {
+	if !s.Alive(e) {
+		return fmt.Errorf("failed to delete entity %v: %w", e, ErrEntityNotFound)
+	}
 	// Check for errors and return the first one that is not a "not found" error.
 	for _, err := range s.deleteAllEntityComponents(e) {
 		if err != nil && !errors.Is(err, ErrEntityNotFound) {
 			return fmt.Errorf("failed to delete entity: %w", err)
 		}
 	}
+	if err := s.deleteRelationsTo(e); err != nil {
+		return fmt.Errorf("failed to delete entity: %w", err)
+	}
+	s.destroy(e)
+	if err := s.publish(entity.NewDeletedEvent(e)); err != nil {
+		return fmt.Errorf("failed to publish delete event: %w", err)
+	}
 	return nil
 }
 
 // deleteAllEntityComponents removes an entity and all its associated components from the ECS.
 func (s *ECS) deleteAllEntityComponents(e entity.Entity) []error {
 	var errs []error
-	for _, t := range componentTypes() {
-		switch t {
-		case position.Type:
-			if err := s.deletePositionByEntity(e); err != nil {
-				errs = append(errs, err)
-			}
-		case controllable.Type:
-			if err := s.deleteControllableByEntity(e); err != nil {
-				errs = append(errs, err)
-			}
-		case shape.RectangleType:
-			if err := s.deleteRectanglesByEntity(e); err != nil {
-				errs = append(errs, err)
-			}
-		case sprite.Type:
-			if err := s.deleteSpritesByEntity(e); err != nil {
-				errs = append(errs, err)
-			}
-		case skeleton.Type:
-			if err := s.deleteSkeletonByEntity(e); err != nil {
-				errs = append(errs, err)
-			}
-		default:
-			errs = append(errs, fmt.Errorf("unknown component type: %v", t))
+	for _, r := range s.stores.types {
+		if err := r.deleteByEntity(s, e); err != nil {
+			errs = append(errs, err)
 		}
 	}
 	return errs
 }
 
 // GetAbsolutePosition returns the absolute position of an entity in the ECS.
-// The absolute position is the sum of the entity's position and the position of all its ancestors.
+// It is the translation of the world transform of the entity, rounded to the nearest point.
+// Without rotation and scale it is the sum of the entity's position and the position of all its ancestors.
 func (s *ECS) GetAbsolutePosition(e entity.Entity) (point.Point, error) {
-	if e == s.hierarchy.Root() {
-		return point.Point{}, nil
-	}
-	parent, err := s.hierarchy.Parent(e)
-	if err != nil {
-		return point.Point{}, err
-	}
-	pos, err := s.GetPosition(e)
-	if err != nil {
-		return point.Point{}, err
-	}
-	p, err := s.GetAbsolutePosition(parent)
+	m, err := s.WorldTransform(e)
 	if err != nil {
 		return point.Point{}, err
 	}
-	return p.Add(pos.Cords()), nil
+	return m.Point(), nil
 }
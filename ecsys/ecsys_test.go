@@ -35,9 +35,9 @@ func TestECS_CreateEntity(t *testing.T) {
 		}
 
 		// Check if the entity has a position component
-		pos, err := ecs.GetPosition(entity)
+		pos, err := ecsys.Get[position.Position](ecs, entity)
 		if err != nil {
-			t.Errorf("GetPosition() error = %v", err)
+			t.Errorf("Get() error = %v", err)
 		}
 
 		expected := position.Position{
@@ -47,7 +47,7 @@ func TestECS_CreateEntity(t *testing.T) {
 		}
 
 		if diff := cmp.Diff(pos, expected); diff != "" {
-			t.Errorf("GetPosition() mismatch (-want +got):\n%s", diff)
+			t.Errorf("Get() mismatch (-want +got):\n%s", diff)
 		}
 	})
 }
@@ -61,16 +61,16 @@ func TestECS_DeleteEntity(t *testing.T) {
 		}
 
 		// add sprite component
-		if _, err = ecs.AddSprite(sprite.Sprite{
+		if _, err = ecsys.Add(ecs, sprite.Sprite{
 			E:       entity,
 			I:       1,
 			Graphic: sprite.SkeletonDeath1,
 		}); err != nil {
-			t.Errorf("AddSprite() error = %v", err)
+			t.Errorf("Add() error = %v", err)
 		}
 
 		// check if the sprite has been added
-		if l := len(ecs.ListSprites(entity)); l != 1 {
+		if l := len(ecsys.List[sprite.Sprite](ecs, entity)); l != 1 {
 			t.Errorf("SpritesByEntity() sprites = %v", l)
 		}
 
@@ -79,23 +79,44 @@ func TestECS_DeleteEntity(t *testing.T) {
 		}
 
 		// Check if the entity has been deleted
-		_, err = ecs.GetPosition(entity)
+		_, err = ecsys.Get[position.Position](ecs, entity)
 		if !errors.Is(err, ecsys.ErrEntityNotFound) {
-			t.Errorf("GetPosition() error = %v", err)
+			t.Errorf("Get() error = %v", err)
 		}
 
 		// Check if the sprite has been deleted
-		if l := len(ecs.ListSprites(entity)); l != 0 {
+		if l := len(ecsys.List[sprite.Sprite](ecs, entity)); l != 0 {
 			t.Errorf("SpritesByEntity() sprites = %v", l)
 		}
 	})
+
+	t.Run("should publish the entity events around the component events", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		var published []string
+		bus.Subscribe(event.MatchAll(), func(e event.Event) error {
+			published = append(published, e.Event())
+			return nil
+		})
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if err = ecs.DeleteEntity(e); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		want := []string{entity.CreatedEventType, position.CreatedEventType, position.DeletedEventType, entity.DeletedEventType}
+		if diff := cmp.Diff(want, published); diff != "" {
+			t.Errorf("published mismatch (-want +got):\n%s", diff)
+		}
+	})
 }
 
 func TestECS_CreateEmptyEntity(t *testing.T) {
 	ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
 	for i := range 100 {
-		if e := ecs.CreateEmptyEntity(); e != entity.Entity(i+1) {
-			t.Errorf("expected entity %d, got %d", i+1, e)
+		if e, err := ecs.CreateEmptyEntity(); err != nil || e != entity.Entity(i+1) {
+			t.Errorf("expected entity %d, got %d (error %v)", i+1, e, err)
 		}
 	}
 }
@@ -136,3 +157,60 @@ func TestECS_GetAbsolutePosition(t *testing.T) {
 		}
 	})
 }
+
+func TestECS_Alive(t *testing.T) {
+	t.Run("should reuse the index of a deleted entity with a new generation", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e1, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if !ecs.Alive(e1) {
+			t.Errorf("Alive() = false, want true")
+		}
+
+		if err = ecs.DeleteEntity(e1); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if ecs.Alive(e1) {
+			t.Errorf("Alive() = true, want false")
+		}
+
+		e2, err := ecs.CreateEntity(ecs.Root(), point.New(2, 2))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if e2.Index() != e1.Index() || e2.Generation() != e1.Generation()+1 {
+			t.Errorf("CreateEntity() = %v, want index %d with generation %d", e2, e1.Index(), e1.Generation()+1)
+		}
+
+		// The stale handle must not reach the components of the new entity.
+		if _, err = ecsys.Get[position.Position](ecs, e1); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("Get() error = %v, want ErrEntityNotFound", err)
+		}
+		if _, err = ecsys.Add(ecs, sprite.Sprite{E: e1}); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("Add() error = %v, want ErrEntityNotFound", err)
+		}
+		if err = ecs.DeleteEntity(e1); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("DeleteEntity() error = %v, want ErrEntityNotFound", err)
+		}
+		if pos, err := ecsys.Get[position.Position](ecs, e2); err != nil || pos.Point != point.New(2, 2) {
+			t.Errorf("Get() = %v, %v", pos, err)
+		}
+	})
+
+	t.Run("should track entities that get a component without being created", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := entity.New(5, 2)
+		if _, err := ecsys.Add(ecs, *position.New(ecs.Root(), e, point.Zero())); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if !ecs.Alive(e) {
+			t.Errorf("Alive() = false, want true")
+		}
+		// The free indices below the tracked entity are used first.
+		if n, err := ecs.CreateEmptyEntity(); err != nil || n.Index() >= e.Index() {
+			t.Errorf("CreateEmptyEntity() = %v, %v, want an index below %d", n, err, e.Index())
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package ecsys
+
+import (
+	"slices"
+
+	"github.com/dwethmar/vork/entity"
+)
+
+// entities keeps track of which entity indices are in use and their generation.
+// Indices of deleted entities are put on a free list and reused with a new generation.
+type entities struct {
+	generations []uint32 // generation per index
+	alive       []bool   // alive per index
+	free        []uint32 // free indices that can be reused
+}
+
+// newEntities creates an entity table with the root entity alive.
+func newEntities(root entity.Entity) *entities {
+	t := &entities{}
+	t.track(root)
+	return t
+}
+
+// create returns a new entity, reusing a free index if there is one.
+func (t *entities) create() entity.Entity {
+	var index uint32
+	if n := len(t.free); n > 0 {
+		index = t.free[n-1]
+		t.free = t.free[:n-1]
+	} else {
+		index = uint32(len(t.generations))
+		t.generations = append(t.generations, 0)
+		t.alive = append(t.alive, false)
+	}
+	t.alive[index] = true
+	return entity.New(index, t.generations[index])
+}
+
+// isAlive reports whether the entity is alive and its generation is current.
+func (t *entities) isAlive(e entity.Entity) bool {
+	i := e.Index()
+	return int(i) < len(t.generations) && t.alive[i] && t.generations[i] == e.Generation()
+}
+
+// destroy marks the entity as deleted, bumps the generation of its index and puts the index on the free list.
+func (t *entities) destroy(e entity.Entity) bool {
+	if !t.retire(e) {
+		return false
+	}
+	t.free = append(t.free, e.Index())
+	return true
+}
+
+// retire marks the entity as deleted and bumps the generation of its index, but keeps the index
+// off the free list. It is used in transactions, so the index is not reused before the deletion is
+// committed, see revive and free.
+func (t *entities) retire(e entity.Entity) bool {
+	if !t.isAlive(e) {
+		return false
+	}
+	i := e.Index()
+	t.alive[i] = false
+	t.generations[i]++
+	return true
+}
+
+// revive marks a retired entity as alive again with its old generation.
+func (t *entities) revive(e entity.Entity) {
+	i := e.Index()
+	t.generations[i] = e.Generation()
+	t.alive[i] = true
+}
+
+// freeIndex puts the index of a retired entity on the free list.
+func (t *entities) freeIndex(e entity.Entity) {
+	if i := e.Index(); !t.alive[i] {
+		t.free = append(t.free, i)
+	}
+}
+
+// release puts the index of an entity that was created in a rolled back transaction back on the
+// free list. Its generation is not bumped, no handle to it has been published.
+func (t *entities) release(e entity.Entity) {
+	i := e.Index()
+	t.alive[i] = false
+	t.free = append(t.free, i)
+}
+
+// restorer returns a function that restores the index to its current state.
+// An index that is not known yet is restored as a free index.
+func (t *entities) restorer(i uint32) func() {
+	generation, alive, free := uint32(0), false, true
+	if int(i) < len(t.generations) {
+		generation, alive = t.generations[i], t.alive[i]
+		free = slices.Contains(t.free, i)
+	}
+	return func() {
+		t.generations[i], t.alive[i] = generation, alive
+		t.free = slices.DeleteFunc(t.free, func(f uint32) bool { return f == i })
+		if free {
+			t.free = append(t.free, i)
+		}
+	}
+}
+
+// track marks an entity that was not created by the table as alive, for example
+// an entity whose components were loaded from a save. It returns false if the
+// entity is stale, meaning its index is in use by, or was freed after, a later generation.
+func (t *entities) track(e entity.Entity) bool {
+	i := e.Index()
+	for int(i) >= len(t.generations) {
+		if n := uint32(len(t.generations)); n != i {
+			t.free = append(t.free, n)
+		}
+		t.generations = append(t.generations, 0)
+		t.alive = append(t.alive, false)
+	}
+	switch {
+	case t.alive[i]:
+		return t.generations[i] == e.Generation()
+	case e.Generation() < t.generations[i]:
+		return false
+	}
+	t.free = slices.DeleteFunc(t.free, func(f uint32) bool { return f == i })
+	t.generations[i] = e.Generation()
+	t.alive[i] = true
+	return true
+}
+
+// clone returns a copy of the entity table.
+func (t *entities) clone() *entities {
+	return &entities{
+		generations: slices.Clone(t.generations),
+		alive:       slices.Clone(t.alive),
+		free:        slices.Clone(t.free),
+	}
+}
+
+// list returns the alive entities in index order.
+func (t *entities) list() []entity.Entity {
+	var l []entity.Entity
+	for i, alive := range t.alive {
+		if alive {
+			l = append(l, entity.New(uint32(i), t.generations[i]))
+		}
+	}
+	return l
+}
@@ -3,41 +3,21 @@ package ecsys
 import (
 	"fmt"
 
-	"github.com/dwethmar/vork/component/controllable"
-	"github.com/dwethmar/vork/component/position"
-	"github.com/dwethmar/vork/component/skeleton"
-	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/entity"
 )
 
-func (s *ECS) GetPosition(e entity.Entity) (position.Position, error) {
-	c, err := s.stores.Position.First(e)
+// Get returns the first component of a registered type associated with an entity.
+func Get[C any, T ComponentPointer[C]](s *ECS, e entity.Entity) (C, error) {
+	store, err := StoreFor[T](s.stores)
 	if err != nil {
-		return position.Position{}, fmt.Errorf("could not get position of entity %d: %w", e, err)
+		return *new(C), err
 	}
-	return *c, nil
-}
-
-func (s *ECS) GetVelocity(e entity.Entity) (velocity.Velocity, error) {
-	c, err := s.stores.Velocity.First(e)
-	if err != nil {
-		return velocity.Velocity{}, fmt.Errorf("could not get velocity of entity %d: %w", e, err)
-	}
-	return *c, nil
-}
-
-func (s *ECS) GetControllable(e entity.Entity) (controllable.Controllable, error) {
-	c, err := s.stores.Controllable.First(e)
-	if err != nil {
-		return controllable.Controllable{}, fmt.Errorf("could not get controllable of entity %d: %w", e, err)
+	if !s.Alive(e) {
+		return *new(C), fmt.Errorf("could not get %s of entity %v: %w", T(new(C)).Type(), e, ErrEntityNotFound)
 	}
-	return *c, nil
-}
-
-func (s *ECS) GetSkeleton(e entity.Entity) (skeleton.Skeleton, error) {
-	c, err := s.stores.Skeleton.First(e)
+	c, err := store.First(e)
 	if err != nil {
-		return skeleton.Skeleton{}, fmt.Errorf("could not get skeleton of entity %d: %w", e, err)
+		return *new(C), fmt.Errorf("could not get %s of entity %v: %w", T(new(C)).Type(), e, err)
 	}
 	return *c, nil
 }
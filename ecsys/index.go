@@ -0,0 +1,282 @@
+package ecsys
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+// ErrIndexValueTaken is returned when a value of a unique index is already used by another entity.
+var ErrIndexValueTaken = errors.New("index value is already taken")
+
+// Index is a secondary index on a component type. It maps values derived from a component,
+// such as a tag, to the components, so they can be looked up without scanning the store.
+type Index[T component.Component] struct {
+	// Name is the name of the index, it must be unique across all component types.
+	Name string
+	// Unique enforces that a value is used by at most one component.
+	Unique bool
+	// Values returns the values the component is indexed by.
+	Values func(T) []string
+}
+
+// indexed holds the entity and values of an indexed component.
+type indexed struct {
+	entity entity.Entity
+	values []string
+}
+
+// index holds the components of a component type by the values of an Index.
+type index struct {
+	name       string
+	unique     bool
+	values     func(component.Component) []string
+	components map[string]map[entity.Entity][]uint // value -> entity -> component IDs
+	byID       map[uint]indexed                    // component ID -> entity and values
+}
+
+func (i *index) clear() {
+	i.components = make(map[string]map[entity.Entity][]uint)
+	i.byID = make(map[uint]indexed)
+}
+
+// check returns ErrIndexValueTaken if the component cannot be indexed because
+// one of its values is used by another component in a unique index.
+func (i *index) check(c component.Component, values []string) error {
+	if !i.unique {
+		return nil
+	}
+	for _, v := range values {
+		for e, ids := range i.components[v] {
+			if slices.ContainsFunc(ids, func(id uint) bool { return id != c.ID() }) {
+				return fmt.Errorf("%w: %s %q is used by entity %v", ErrIndexValueTaken, i.name, v, e)
+			}
+		}
+	}
+	return nil
+}
+
+func (i *index) add(c component.Component, values []string) {
+	i.byID[c.ID()] = indexed{entity: c.Entity(), values: values}
+	for _, v := range values {
+		entities, ok := i.components[v]
+		if !ok {
+			entities = make(map[entity.Entity][]uint)
+			i.components[v] = entities
+		}
+		if !slices.Contains(entities[c.Entity()], c.ID()) {
+			entities[c.Entity()] = append(entities[c.Entity()], c.ID())
+		}
+	}
+}
+
+func (i *index) remove(id uint) {
+	ix, ok := i.byID[id]
+	if !ok {
+		return
+	}
+	delete(i.byID, id)
+	for _, v := range ix.values {
+		entities := i.components[v]
+		entities[ix.entity] = slices.DeleteFunc(entities[ix.entity], func(x uint) bool { return x == id })
+		if len(entities[ix.entity]) == 0 {
+			delete(entities, ix.entity)
+		}
+		if len(entities) == 0 {
+			delete(i.components, v)
+		}
+	}
+}
+
+// indexes holds the indexes of all registered component types.
+type indexes struct {
+	mu     sync.RWMutex
+	byName map[string]*index
+	byType map[component.Type][]*index
+}
+
+func newIndexes(types []registered) *indexes {
+	ix := &indexes{
+		byName: make(map[string]*index),
+		byType: make(map[component.Type][]*index),
+	}
+	for _, r := range types {
+		for _, i := range r.indexes() {
+			i.clear()
+			ix.byName[i.name] = i
+			ix.byType[r.componentType()] = append(ix.byType[r.componentType()], i)
+		}
+	}
+	return ix
+}
+
+// add indexes a component that was added to or updated in its store. It returns
+// ErrIndexValueTaken, and leaves the indexes unchanged, if a unique value is taken.
+func (ix *indexes) add(t component.Type, c component.Component) error {
+	idx := ix.byType[t]
+	if len(idx) == 0 {
+		return nil
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	values := make([][]string, len(idx))
+	for n, i := range idx {
+		values[n] = i.values(c)
+		if err := i.check(c, values[n]); err != nil {
+			return err
+		}
+	}
+	for n, i := range idx {
+		i.remove(c.ID())
+		i.add(c, values[n])
+	}
+	return nil
+}
+
+// remove removes a component from the indexes of its type.
+func (ix *indexes) remove(t component.Type, id uint) {
+	idx := ix.byType[t]
+	if len(idx) == 0 {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for _, i := range idx {
+		i.remove(id)
+	}
+}
+
+// lookup returns the component IDs per entity that are indexed by the value.
+func (ix *indexes) lookup(name, value string) map[entity.Entity][]uint {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	i, ok := ix.byName[name]
+	if !ok {
+		return nil
+	}
+	m := make(map[entity.Entity][]uint, len(i.components[value]))
+	for e, ids := range i.components[value] {
+		m[e] = slices.Clone(ids)
+	}
+	return m
+}
+
+// BuildIndexes rebuilds the indexes from the components in the stores.
+// It is used after components have been loaded directly into the stores.
+func (s *ECS) BuildIndexes() error {
+	s.indexes.mu.Lock()
+	for _, idx := range s.indexes.byType {
+		for _, i := range idx {
+			i.clear()
+		}
+	}
+	s.indexes.mu.Unlock()
+	for _, r := range s.stores.types {
+		for _, c := range r.list(s.stores) {
+			if err := s.indexes.add(r.componentType(), c); err != nil {
+				return fmt.Errorf("could not index %s %d: %w", r.componentType(), c.ID(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// LookupEntities returns the entities that have a component indexed by the value, ordered by entity.
+// It returns nil if there is no index with the name.
+func (s *ECS) LookupEntities(index, value string) []entity.Entity {
+	return slices.Sorted(maps.Keys(s.indexes.lookup(index, value)))
+}
+
+// Lookup returns the components of type C that are indexed by the value, ordered by ID.
+func Lookup[C any, T ComponentPointer[C]](s *ECS, index, value string) []C {
+	var ids []uint
+	for _, x := range s.indexes.lookup(index, value) {
+		ids = append(ids, x...)
+	}
+	slices.Sort(ids)
+	return getByID[C, T](s, ids)
+}
+
+// LookupByEntity returns the components of type C of the entity that are indexed by the value, ordered by ID.
+func LookupByEntity[C any, T ComponentPointer[C]](s *ECS, e entity.Entity, index, value string) []C {
+	ids := s.indexes.lookup(index, value)[e]
+	slices.Sort(ids)
+	return getByID[C, T](s, ids)
+}
+
+// getByID returns the components of type C with the IDs, skipping IDs that are not in the store.
+func getByID[C any, T ComponentPointer[C]](s *ECS, ids []uint) []C {
+	store, err := StoreFor[T](s.stores)
+	if err != nil || len(ids) == 0 {
+		return nil
+	}
+	comps := make([]C, 0, len(ids))
+	for _, id := range ids {
+		if c, err := store.Get(id); err == nil {
+			comps = append(comps, *c)
+		}
+	}
+	return comps
+}
+
+// indexes returns a new, empty index for every Index of the registration.
+func (r Registration[T]) indexes() []*index {
+	idx := make([]*index, 0, len(r.Indexes))
+	for _, i := range r.Indexes {
+		values := i.Values
+		idx = append(idx, &index{
+			name:   i.Name,
+			unique: i.Unique,
+			values: func(c component.Component) []string {
+				comp, ok := c.(T)
+				if !ok {
+					return nil
+				}
+				return values(comp)
+			},
+		})
+	}
+	return idx
+}
+
+// list returns all components of type T, ordered by ID.
+func (r Registration[T]) list(s *Stores) []component.Component {
+	store, err := StoreFor[T](s)
+	if err != nil {
+		return nil
+	}
+	comps := store.List()
+	list := make([]component.Component, len(comps))
+	for i, c := range comps {
+		list[i] = c
+	}
+	slices.SortFunc(list, func(a, b component.Component) int { return cmp.Compare(a.ID(), b.ID()) })
+	return list
+}
+
+// FindEntity returns the entity that has a component of type C indexed by the value, for example
+// the entity with a name. If more entities match, the first one in entity order is returned.
+// It returns ErrEntityNotFound if no entity matches or the index is not an index of C.
+func FindEntity[C any, T ComponentPointer[C]](s *ECS, index, value string) (entity.Entity, error) {
+	entities := FindEntities[C, T](s, index, value)
+	if len(entities) == 0 {
+		return 0, fmt.Errorf("no entity with %s %q: %w", index, value, ErrEntityNotFound)
+	}
+	return entities[0], nil
+}
+
+// FindEntities returns the entities that have a component of type C indexed by the value, ordered by
+// entity, for example the entities with a tag. It returns nil if the index is not an index of C.
+func FindEntities[C any, T ComponentPointer[C]](s *ECS, index, value string) []entity.Entity {
+	r, err := lookup[T]()
+	if err != nil || !slices.ContainsFunc(r.Indexes, func(i Index[T]) bool { return i.Name == index }) {
+		return nil
+	}
+	return s.LookupEntities(index, value)
+}
@@ -0,0 +1,194 @@
+package ecsys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/name"
+	"github.com/dwethmar/vork/component/sprite"
+	"github.com/dwethmar/vork/component/tags"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newEntities(t *testing.T, ecs *ecsys.ECS, n int) []entity.Entity {
+	t.Helper()
+	entities := make([]entity.Entity, n)
+	for i := range entities {
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		entities[i] = e
+	}
+	return entities
+}
+
+func TestFindEntity(t *testing.T) {
+	t.Run("should find the entity by its name", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 2)
+		for i, n := range []string{"player", "boss"} {
+			if _, err := ecsys.Add(ecs, *name.New(es[i], n)); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+		got, err := ecsys.FindEntity[name.Name](ecs, name.Index, "boss")
+		if err != nil {
+			t.Fatalf("FindEntity() error = %v", err)
+		}
+		if got != es[1] {
+			t.Errorf("FindEntity() = %v, want %v", got, es[1])
+		}
+	})
+
+	t.Run("should not add a name that is taken by another entity", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 2)
+		if _, err := ecsys.Add(ecs, *name.New(es[0], "player")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err := ecsys.Add(ecs, *name.New(es[1], "player")); !errors.Is(err, ecsys.ErrIndexValueTaken) {
+			t.Fatalf("Add() error = %v, want %v", err, ecsys.ErrIndexValueTaken)
+		}
+		if _, err := ecsys.Get[name.Name](ecs, es[1]); err == nil {
+			t.Error("name should not be added")
+		}
+	})
+
+	t.Run("should free the name when it is changed or its entity is deleted", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 2)
+		if _, err := ecsys.Add(ecs, *name.New(es[0], "player")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		n, err := ecsys.Get[name.Name](ecs, es[0])
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		n.Name = "ghost"
+		if err = ecsys.Update(ecs, n); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if _, err = ecsys.FindEntity[name.Name](ecs, name.Index, "player"); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("FindEntity() error = %v, want %v", err, ecsys.ErrEntityNotFound)
+		}
+		if _, err = ecsys.Add(ecs, *name.New(es[1], "player")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err = ecs.DeleteEntity(es[0]); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if _, err = ecsys.FindEntity[name.Name](ecs, name.Index, "ghost"); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("FindEntity() error = %v, want %v", err, ecsys.ErrEntityNotFound)
+		}
+	})
+
+	t.Run("should remove the name from the index on rollback", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 1)
+		wantErr := errors.New("failed")
+		err := ecs.Tx(func(tx *ecsys.ECS) error {
+			if _, err := ecsys.Add(tx, *name.New(es[0], "player")); err != nil {
+				return err
+			}
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Tx() error = %v, want %v", err, wantErr)
+		}
+		if _, err = ecsys.FindEntity[name.Name](ecs, name.Index, "player"); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("FindEntity() error = %v, want %v", err, ecsys.ErrEntityNotFound)
+		}
+	})
+}
+
+func TestFindEntities(t *testing.T) {
+	t.Run("should return the tagged entities", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 3)
+		if _, err := ecsys.Add(ecs, *tags.New(es[0], "enemy", "undead")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err := ecsys.Add(ecs, *tags.New(es[2], "undead")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if diff := cmp.Diff([]entity.Entity{es[0], es[2]}, ecsys.FindEntities[tags.Tags](ecs, tags.Index, "undead")); diff != "" {
+			t.Errorf("FindEntities() mismatch (-want +got):\n%s", diff)
+		}
+
+		tg, err := ecsys.Get[tags.Tags](ecs, es[0])
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		tg.Remove("undead")
+		tg.Add("boss")
+		if err = ecsys.Update(ecs, tg); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if diff := cmp.Diff([]entity.Entity{es[2]}, ecsys.FindEntities[tags.Tags](ecs, tags.Index, "undead")); diff != "" {
+			t.Errorf("FindEntities() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]entity.Entity{es[0]}, ecsys.FindEntities[tags.Tags](ecs, tags.Index, "boss")); diff != "" {
+			t.Errorf("FindEntities() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should rebuild the index when a snapshot is restored", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 1)
+		snap := ecs.Snapshot()
+		if _, err := ecsys.Add(ecs, *tags.New(es[0], "enemy")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := ecs.Restore(snap); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if got := ecsys.FindEntities[tags.Tags](ecs, tags.Index, "enemy"); len(got) != 0 {
+			t.Errorf("FindEntities() = %v, want none", got)
+		}
+	})
+
+	t.Run("should not look up the index of another type", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 1)
+		if _, err := ecsys.Add(ecs, *tags.New(es[0], "enemy")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if got := ecsys.FindEntities[name.Name](ecs, tags.Index, "enemy"); got != nil {
+			t.Errorf("FindEntities() = %v, want nil", got)
+		}
+	})
+}
+
+func TestLookupByEntity(t *testing.T) {
+	t.Run("should return the components of the entity with the indexed value", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores(ecsys.WithSparseSet[hitbox.Hitbox]()))
+		es := newEntities(t, ecs, 2)
+		for _, e := range es {
+			for _, tag := range []string{"body", "shadow"} {
+				if _, err := ecsys.Add(ecs, *sprite.New(e, tag, sprite.SkeletonMoveDown1)); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+				if _, err := ecsys.Add(ecs, *hitbox.New(e, tag, 1, 1, point.Zero())); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+		}
+
+		sprites := ecsys.LookupByEntity[sprite.Sprite](ecs, es[1], sprite.TagIndex, "shadow")
+		if len(sprites) != 1 || sprites[0].Entity() != es[1] || sprites[0].Tag != "shadow" {
+			t.Errorf("LookupByEntity() = %+v, want the shadow sprite of entity %v", sprites, es[1])
+		}
+		if got := ecsys.Lookup[hitbox.Hitbox](ecs, hitbox.TagIndex, "body"); len(got) != 2 {
+			t.Errorf("Lookup() len = %d, want 2", len(got))
+		}
+		if got := ecs.LookupEntities("unknown", "body"); got != nil {
+			t.Errorf("LookupEntities() = %v, want nil for an unknown index", got)
+		}
+	})
+}
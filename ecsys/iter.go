@@ -0,0 +1,144 @@
+package ecsys
+
+import (
+	"iter"
+
+	"github.com/dwethmar/vork/entity"
+)
+
+// The iterators hold a read lock on the store they iterate over for the whole loop. The other
+// stores they read, such as the stores of the other components of a row, the stores of the types
+// excluded by the options and the active store, are locked only while a row is read, as are the
+// hierarchy and the cache of active states. So a loop may change components of any type except
+// the iterated one, for example update the B of a row in IterQuery2. Changes that delete or add
+// components of the iterated type, such as deleting the entity of the row, must be made after the loop.
+// A store must not be read-locked twice, so the active components can only be iterated with
+// IncludeInactive and the iterated type cannot be excluded with Without.
+
+// Iter returns an iterator over the entities and components of a registered type.
+// Entities that are excluded by the options, or are inactive, are skipped.
+// Unlike All, the components are not copied to a slice first. The store of type C is
+// read-locked while iterating, so the loop must not add, update or delete components of type C.
+func Iter[C any, T ComponentPointer[C]](s *ECS, opts ...QueryOption) iter.Seq2[entity.Entity, C] {
+	return func(yield func(entity.Entity, C) bool) {
+		q := newQuery(opts)
+		store, err := StoreFor[T](s.stores)
+		if err != nil {
+			return
+		}
+		for c := range store.Iter() {
+			if q.excluded(s, c.Entity()) {
+				continue
+			}
+			if !yield(c.Entity(), *c) {
+				return
+			}
+		}
+	}
+}
+
+// IterByEntity returns an iterator over the components of a registered type associated with an entity.
+// The store of type C is read-locked while iterating, so the loop must not add, update or delete
+// components of type C.
+func IterByEntity[C any, T ComponentPointer[C]](s *ECS, e entity.Entity) iter.Seq[C] {
+	return func(yield func(C) bool) {
+		store, err := StoreFor[T](s.stores)
+		if err != nil || !s.Alive(e) {
+			return
+		}
+		for c := range store.IterByEntity(e) {
+			if !yield(*c) {
+				return
+			}
+		}
+	}
+}
+
+// IterQuery1 returns an iterator over the rows of Query1.
+// The store of type A is read-locked while iterating, so the loop must not add, update or delete
+// components of type A.
+func IterQuery1[A any, PA ComponentPointer[A]](s *ECS, opts ...QueryOption) iter.Seq[Row1[A]] {
+	return func(yield func(Row1[A]) bool) {
+		q := newQuery(opts)
+		as, err := StoreFor[PA](s.stores)
+		if err != nil {
+			return
+		}
+		for a := range as.Iter() {
+			e := a.Entity()
+			if q.excluded(s, e) {
+				continue
+			}
+			if !yield(Row1[A]{Entity: e, A: *a}) {
+				return
+			}
+		}
+	}
+}
+
+// IterQuery2 returns an iterator over the rows of Query2.
+// The store of type A is read-locked while iterating, the store of type B only while a row is read.
+// The loop must not add, update or delete components of type A, it may change components of type B.
+func IterQuery2[A, B any, PA ComponentPointer[A], PB ComponentPointer[B]](s *ECS, opts ...QueryOption) iter.Seq[Row2[A, B]] {
+	return func(yield func(Row2[A, B]) bool) {
+		q := newQuery(opts)
+		as, err := StoreFor[PA](s.stores)
+		if err != nil {
+			return
+		}
+		bs, err := StoreFor[PB](s.stores)
+		if err != nil {
+			return
+		}
+		for a := range as.Iter() {
+			e := a.Entity()
+			b, bErr := bs.First(e)
+			if bErr != nil || q.excluded(s, e) {
+				continue
+			}
+			if !yield(Row2[A, B]{Entity: e, A: *a, B: *b}) {
+				return
+			}
+		}
+	}
+}
+
+// IterQuery3 returns an iterator over the rows of Query3.
+// The store of type A is read-locked while iterating, the stores of type B and C only while a row is read.
+// The loop must not add, update or delete components of type A, it may change components of type B and C.
+func IterQuery3[
+	A, B, C any,
+	PA ComponentPointer[A],
+	PB ComponentPointer[B],
+	PC ComponentPointer[C],
+](s *ECS, opts ...QueryOption) iter.Seq[Row3[A, B, C]] {
+	return func(yield func(Row3[A, B, C]) bool) {
+		q := newQuery(opts)
+		as, err := StoreFor[PA](s.stores)
+		if err != nil {
+			return
+		}
+		bs, err := StoreFor[PB](s.stores)
+		if err != nil {
+			return
+		}
+		cs, err := StoreFor[PC](s.stores)
+		if err != nil {
+			return
+		}
+		for a := range as.Iter() {
+			e := a.Entity()
+			b, bErr := bs.First(e)
+			if bErr != nil {
+				continue
+			}
+			c, cErr := cs.First(e)
+			if cErr != nil || q.excluded(s, e) {
+				continue
+			}
+			if !yield(Row3[A, B, C]{Entity: e, A: *a, B: *b, C: *c}) {
+				return
+			}
+		}
+	}
+}
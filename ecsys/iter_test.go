@@ -0,0 +1,70 @@
+package ecsys_test
+
+import (
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIter(t *testing.T) {
+	t.Run("should iterate entities and components", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		a, _ := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		b, _ := ecs.CreateEntity(ecs.Root(), point.New(2, 2))
+		if _, err := ecsys.Add(ecs, *velocity.New(b, point.New(1, 0))); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		got := map[entity.Entity]point.Point{}
+		for e, p := range ecsys.Iter[position.Position](ecs) {
+			got[e] = p.Point
+		}
+		want := map[entity.Entity]point.Point{a: point.New(1, 1), b: point.New(2, 2)}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Iter() mismatch (-want +got):\n%s", diff)
+		}
+
+		var rows []entity.Entity
+		for r := range ecsys.IterQuery2[velocity.Velocity, position.Position](ecs) {
+			rows = append(rows, r.Entity)
+		}
+		if diff := cmp.Diff([]entity.Entity{b}, rows); diff != "" {
+			t.Errorf("IterQuery2() mismatch (-want +got):\n%s", diff)
+		}
+
+		n := 0
+		for range ecsys.IterByEntity[velocity.Velocity](ecs, b) {
+			n++
+		}
+		if n != 1 {
+			t.Errorf("IterByEntity() yielded %d components, want 1", n)
+		}
+	})
+
+	t.Run("should not allocate per component", func(t *testing.T) {
+		allocs := func(n int) float64 {
+			ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+			for i := range n {
+				if _, err := ecs.CreateEntity(ecs.Root(), point.New(i, i)); err != nil {
+					t.Fatalf("CreateEntity() error = %v", err)
+				}
+			}
+			return testing.AllocsPerRun(10, func() {
+				sum := 0
+				for _, p := range ecsys.Iter[position.Position](ecs) {
+					sum += p.X
+				}
+				_ = sum
+			})
+		}
+		if small, large := allocs(10), allocs(1000); large > small {
+			t.Errorf("allocations grow with the number of components: %v for 10, %v for 1000", small, large)
+		}
+	})
+}
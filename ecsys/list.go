@@ -1,32 +1,21 @@
 package ecsys
 
-import (
-	"github.com/dwethmar/vork/component/hitbox"
-	"github.com/dwethmar/vork/component/shape"
-	"github.com/dwethmar/vork/component/sprite"
-	"github.com/dwethmar/vork/entity"
-)
+import "github.com/dwethmar/vork/entity"
 
 // derefSlice dereferences a slice of pointers to a slice of values.
-func derefSlice[T any](s []*T) []T {
-	r := make([]T, len(s))
+func derefSlice[C any, T ComponentPointer[C]](s []T) []C {
+	r := make([]C, len(s))
 	for i, v := range s {
 		r[i] = *v
 	}
 	return r
 }
 
-// ListPositionsByEntity returns all positions for a given entity.
-func (s *ECS) ListHitboxes(e entity.Entity) []hitbox.Hitbox {
-	return derefSlice(s.stores.Hitbox.ListByEntity(e))
-}
-
-// ListPositionsByEntity returns all positions for a given entity.
-func (s *ECS) ListRectangles(e entity.Entity) []shape.Rectangle {
-	return derefSlice(s.stores.Rectangle.ListByEntity(e))
-}
-
-// SpritesByEntity returns all sprites for a given entity.
-func (s *ECS) ListSprites(e entity.Entity) []sprite.Sprite {
-	return derefSlice(s.stores.Sprite.ListByEntity(e))
+// List returns all components of a registered type associated with an entity.
+func List[C any, T ComponentPointer[C]](s *ECS, e entity.Entity) []C {
+	store, err := StoreFor[T](s.stores)
+	if err != nil || !s.Alive(e) {
+		return nil
+	}
+	return derefSlice[C, T](store.ListByEntity(e))
 }
@@ -2,6 +2,7 @@ package ecsys
 
 import (
 	"fmt"
+	"iter"
 	"sort"
 	"sync"
 
@@ -73,6 +74,10 @@ func (s *MemStore[C]) Add(c C) (uint, error) {
 		if index < len(s.components) && (*s.components[index]).ID() == c.ID() {
 			return 0, fmt.Errorf("component with ID %d already exists", c.ID())
 		}
+		// Make sure generated IDs do not collide with the given one.
+		if c.ID() >= s.nextID {
+			s.nextID = c.ID() + 1
+		}
 	}
 
 	// Insert the component into the sorted slice
@@ -154,6 +159,34 @@ func (s *MemStore[C]) List() []C {
 	return components
 }
 
+// Iter returns an iterator over all components in the store, ordered by ID.
+// The store is read-locked while iterating.
+func (s *MemStore[C]) Iter() iter.Seq[C] {
+	return func(yield func(C) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, compPtr := range s.components {
+			if !yield(*compPtr) {
+				return
+			}
+		}
+	}
+}
+
+// IterByEntity returns an iterator over the components associated with an entity.
+// The store is read-locked while iterating.
+func (s *MemStore[C]) IterByEntity(e entity.Entity) iter.Seq[C] {
+	return func(yield func(C) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, compPtr := range s.entityIndex[e] {
+			if !yield(*compPtr) {
+				return
+			}
+		}
+	}
+}
+
 // First retrieves the first component associated with an entity.
 func (s *MemStore[C]) First(e entity.Entity) (C, error) {
 	s.mu.RLock()
@@ -0,0 +1,138 @@
+package ecsys
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+)
+
+// observers holds the typed observers of component changes per component type.
+// Unlike events, observers are called for every registered component type.
+type observers struct {
+	mu     sync.RWMutex
+	byType map[component.Type][]observer
+	nextID int // Used to assign a unique ID to each observer
+}
+
+// observer is the type-erased form of an OnAdd, OnRemove or OnChange function.
+type observer struct {
+	id     int
+	add    func(c component.Component) error
+	remove func(c component.Component) error
+	change func(old, c component.Component) error
+}
+
+func newObservers() *observers {
+	return &observers{
+		byType: make(map[component.Type][]observer),
+		nextID: 1, // Start IDs from 1
+	}
+}
+
+// OnAdd calls fn with a copy of every component of type C that is added to the ECS.
+// It returns an identifier for the observer, which can be used to remove it with Unobserve.
+func OnAdd[C any, T ComponentPointer[C]](s *ECS, fn func(c C) error) (int, error) {
+	r, err := lookup[T]()
+	if err != nil {
+		return 0, err
+	}
+	return s.observers.observe(r.Type, observer{
+		add: func(c component.Component) error { return fn(*c.(T)) },
+	}), nil
+}
+
+// OnRemove calls fn with a copy of every component of type C that is deleted from the ECS,
+// including the components that are deleted along with their entity.
+// It returns an identifier for the observer, which can be used to remove it with Unobserve.
+func OnRemove[C any, T ComponentPointer[C]](s *ECS, fn func(c C) error) (int, error) {
+	r, err := lookup[T]()
+	if err != nil {
+		return 0, err
+	}
+	return s.observers.observe(r.Type, observer{
+		remove: func(c component.Component) error { return fn(*c.(T)) },
+	}), nil
+}
+
+// OnChange calls fn with copies of the old and new value of every component of type C that is updated,
+// including the updates that are marked dirty and published at the end of the frame.
+// It returns an identifier for the observer, which can be used to remove it with Unobserve.
+func OnChange[C any, T ComponentPointer[C]](s *ECS, fn func(old, c C) error) (int, error) {
+	r, err := lookup[T]()
+	if err != nil {
+		return 0, err
+	}
+	return s.observers.observe(r.Type, observer{
+		change: func(old, c component.Component) error { return fn(*old.(T), *c.(T)) },
+	}), nil
+}
+
+// Unobserve removes the observer with the identifier returned by OnAdd, OnRemove or OnChange.
+func (s *ECS) Unobserve(id int) {
+	s.observers.mu.Lock()
+	defer s.observers.mu.Unlock()
+	for t, list := range s.observers.byType {
+		s.observers.byType[t] = slices.DeleteFunc(list, func(o observer) bool { return o.id == id })
+	}
+}
+
+// observe adds the observer for the component type and returns its identifier.
+func (o *observers) observe(t component.Type, obs observer) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	obs.id = o.nextID
+	o.nextID++
+	o.byType[t] = append(o.byType[t], obs)
+	return obs.id
+}
+
+// list returns a copy of the observers of the component type, so they can change the observers while being called.
+func (o *observers) list(t component.Type) []observer {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return slices.Clone(o.byType[t])
+}
+
+// notifyAdd calls the OnAdd observers of the component type.
+func (s *ECS) notifyAdd(t component.Type, c component.Component) error {
+	return s.notify(t, func(o observer) error {
+		if o.add == nil {
+			return nil
+		}
+		return o.add(c)
+	})
+}
+
+// notifyRemove calls the OnRemove observers of the component type.
+func (s *ECS) notifyRemove(t component.Type, c component.Component) error {
+	return s.notify(t, func(o observer) error {
+		if o.remove == nil {
+			return nil
+		}
+		return o.remove(c)
+	})
+}
+
+// notifyChange calls the OnChange observers of the component type.
+func (s *ECS) notifyChange(t component.Type, old, c component.Component) error {
+	return s.notify(t, func(o observer) error {
+		if o.change == nil {
+			return nil
+		}
+		return o.change(old, c)
+	})
+}
+
+// notify calls the observers of the component type, or holds the calls back until commit in a transaction.
+func (s *ECS) notify(t component.Type, call func(o observer) error) error {
+	return s.dispatch(func() error {
+		for _, o := range s.observers.list(t) {
+			if err := call(o); err != nil {
+				return fmt.Errorf("observer of %s failed: %w", t, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,219 @@
+package ecsys_test
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/dwethmar/vork/component/shape"
+	"github.com/dwethmar/vork/component/tags"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOnAdd(t *testing.T) {
+	t.Run("should observe components that publish no events", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		var added []shape.Rectangle
+		if _, err := ecsys.OnAdd(ecs, func(r shape.Rectangle) error {
+			added = append(added, r)
+			return nil
+		}); err != nil {
+			t.Fatalf("OnAdd() error = %v", err)
+		}
+
+		e := newEntities(t, ecs, 1)[0]
+		r := shape.NewRectangle(e, 10, 20, color.NRGBA{R: 0xff, A: 0xff})
+		id, err := ecsys.Add(ecs, *r)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		r.I = id
+		if diff := cmp.Diff([]shape.Rectangle{*r}, added); diff != "" {
+			t.Errorf("added mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should return the error of the observer", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		errObserver := errors.New("observer failed")
+		if _, err := ecsys.OnAdd(ecs, func(shape.Rectangle) error { return errObserver }); err != nil {
+			t.Fatalf("OnAdd() error = %v", err)
+		}
+		e := newEntities(t, ecs, 1)[0]
+		if _, err := ecsys.Add(ecs, *shape.NewRectangle(e, 1, 1, color.NRGBA{})); !errors.Is(err, errObserver) {
+			t.Errorf("Add() error = %v, want %v", err, errObserver)
+		}
+	})
+
+	t.Run("should hold back observers until the transaction is committed", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		added := 0
+		if _, err := ecsys.OnAdd(ecs, func(shape.Rectangle) error {
+			added++
+			return nil
+		}); err != nil {
+			t.Fatalf("OnAdd() error = %v", err)
+		}
+		e := newEntities(t, ecs, 1)[0]
+
+		errRollback := errors.New("roll back")
+		err := ecs.Tx(func(tx *ecsys.ECS) error {
+			if _, err := ecsys.Add(tx, *shape.NewRectangle(e, 1, 1, color.NRGBA{})); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Tx() error = %v, want %v", err, errRollback)
+		}
+		if added != 0 {
+			t.Errorf("expected no calls after rollback, got %d", added)
+		}
+
+		err = ecs.Tx(func(tx *ecsys.ECS) error {
+			if _, err := ecsys.Add(tx, *shape.NewRectangle(e, 1, 1, color.NRGBA{})); err != nil {
+				return err
+			}
+			if added != 0 {
+				t.Errorf("expected no calls before commit, got %d", added)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Tx() error = %v", err)
+		}
+		if added != 1 {
+			t.Errorf("expected 1 call after commit, got %d", added)
+		}
+	})
+}
+
+func TestOnChange(t *testing.T) {
+	t.Run("should pass the old and new value", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		var olds, news []shape.Rectangle
+		if _, err := ecsys.OnChange(ecs, func(old, r shape.Rectangle) error {
+			olds, news = append(olds, old), append(news, r)
+			return nil
+		}); err != nil {
+			t.Fatalf("OnChange() error = %v", err)
+		}
+
+		r := shape.NewRectangle(e, 10, 20, color.NRGBA{})
+		id, err := ecsys.Add(ecs, *r)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		r.I = id
+		old := *r
+		r.Width = 30
+		if err = ecsys.Update(ecs, *r); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if diff := cmp.Diff([]shape.Rectangle{old}, olds); diff != "" {
+			t.Errorf("old mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]shape.Rectangle{*r}, news); diff != "" {
+			t.Errorf("new mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should observe components that are marked dirty", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		changed := 0
+		if _, err := ecsys.OnChange(ecs, func(_, _ shape.Rectangle) error {
+			changed++
+			return nil
+		}); err != nil {
+			t.Fatalf("OnChange() error = %v", err)
+		}
+		r := shape.NewRectangle(e, 10, 20, color.NRGBA{})
+		id, err := ecsys.Add(ecs, *r)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		r.I = id
+		if err = ecsys.MarkDirty(ecs, *r); err != nil {
+			t.Fatalf("MarkDirty() error = %v", err)
+		}
+		if changed != 1 {
+			t.Errorf("expected 1 call, got %d", changed)
+		}
+	})
+
+	t.Run("should pass a copy the observer can keep", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		var kept tags.Tags
+		if _, err := ecsys.OnAdd(ecs, func(tg tags.Tags) error {
+			kept = tg
+			return nil
+		}); err != nil {
+			t.Fatalf("OnAdd() error = %v", err)
+		}
+		if _, err := ecsys.Add(ecs, *tags.New(e, "a", "b")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		kept.Tags[0] = "changed"
+		got, err := ecsys.Get[tags.Tags](ecs, e)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if diff := cmp.Diff([]string{"a", "b"}, got.Tags); diff != "" {
+			t.Errorf("Tags mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestOnRemove(t *testing.T) {
+	t.Run("should observe components deleted along with their entity", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		if _, err := ecsys.Add(ecs, *shape.NewRectangle(e, 1, 1, color.NRGBA{})); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		var removed []entity.Entity
+		if _, err := ecsys.OnRemove(ecs, func(r shape.Rectangle) error {
+			removed = append(removed, r.Entity())
+			return nil
+		}); err != nil {
+			t.Fatalf("OnRemove() error = %v", err)
+		}
+
+		if err := ecs.DeleteEntity(e); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if diff := cmp.Diff([]entity.Entity{e}, removed); diff != "" {
+			t.Errorf("removed mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should not call the observer after Unobserve", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := newEntities(t, ecs, 1)[0]
+		removed := 0
+		id, err := ecsys.OnRemove(ecs, func(shape.Rectangle) error {
+			removed++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("OnRemove() error = %v", err)
+		}
+		r := shape.NewRectangle(e, 1, 1, color.NRGBA{})
+		if r.I, err = ecsys.Add(ecs, *r); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		ecs.Unobserve(id)
+		if err = ecsys.Delete(ecs, *r); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if removed != 0 {
+			t.Errorf("expected no calls, got %d", removed)
+		}
+	})
+}
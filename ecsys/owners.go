@@ -0,0 +1,83 @@
+package ecsys
+
+import (
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+)
+
+// owners keeps track of the transactions that last wrote a component. A rollback only undoes the
+// change to a component if the transaction is still its owner, so it does not overwrite a change
+// that another goroutine made to the component after the transaction.
+type owners struct {
+	mu    sync.Mutex
+	types map[component.Type]*typeOwners
+}
+
+func newOwners() *owners {
+	return &owners{types: make(map[component.Type]*typeOwners)}
+}
+
+// typeOwners holds the owners of the components of a type. Its lock is held while a component is
+// written to its store and while a change to it is undone, so the owner changes together with the
+// component. The lock is taken before the lock of the store.
+type typeOwners struct {
+	mu    sync.Mutex
+	owner map[uint]*staging
+}
+
+// of returns the owners of the components of type t.
+func (o *owners) of(t component.Type) *typeOwners {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	to, ok := o.types[t]
+	if !ok {
+		to = &typeOwners{owner: make(map[uint]*staging)}
+		o.types[t] = to
+	}
+	return to
+}
+
+// write calls fn, which writes a component and returns its ID, and makes st the owner of the
+// component. A write outside of a transaction, with st nil, takes the component from its owner.
+func (o *typeOwners) write(st *staging, fn func() (uint, error)) (uint, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	id, err := fn()
+	if err != nil {
+		return 0, err
+	}
+	if st == nil {
+		delete(o.owner, id)
+		return id, nil
+	}
+	if o.owner[id] != st {
+		o.owner[id] = st
+		st.own(o, id)
+	}
+	return id, nil
+}
+
+// guard returns an undo function that only calls undo if st still owns the component with the given ID.
+func (o *typeOwners) guard(st *staging, id uint, undo func() error) func() error {
+	if st == nil || undo == nil {
+		return nil
+	}
+	return func() error {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if o.owner[id] != st {
+			return nil
+		}
+		return undo()
+	}
+}
+
+// release removes st as the owner of the component with the given ID.
+func (o *typeOwners) release(st *staging, id uint) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.owner[id] == st {
+		delete(o.owner, id)
+	}
+}
@@ -0,0 +1,98 @@
+package ecsys
+
+import (
+	"slices"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+)
+
+// QueryOption configures a query.
+type QueryOption func(*query)
+
+// query holds the configuration of a query.
+type query struct {
+	without  []component.Type
+	inactive bool // include inactive entities
+}
+
+// Without excludes entities that have a component of type C from the query.
+func Without[C any, T ComponentPointer[C]]() QueryOption {
+	return func(q *query) {
+		if r, err := lookup[T](); err == nil {
+			q.without = append(q.without, r.Type)
+		}
+	}
+}
+
+// IncludeInactive includes entities that are not active, see SetActive.
+func IncludeInactive() QueryOption {
+	return func(q *query) {
+		q.inactive = true
+	}
+}
+
+func newQuery(opts []QueryOption) *query {
+	q := &query{}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// excluded reports whether the entity has a component of one of the excluded types
+// or is inactive while inactive entities are not included.
+func (q *query) excluded(s *ECS, e entity.Entity) bool {
+	for _, t := range q.without {
+		if s.stores.hasComponent(t, e) {
+			return true
+		}
+	}
+	return !q.inactive && !s.Active(e)
+}
+
+// Row1 is a result of Query1.
+type Row1[A any] struct {
+	Entity entity.Entity
+	A      A
+}
+
+// Row2 is a result of Query2.
+type Row2[A, B any] struct {
+	Entity entity.Entity
+	A      A
+	B      B
+}
+
+// Row3 is a result of Query3.
+type Row3[A, B, C any] struct {
+	Entity entity.Entity
+	A      A
+	B      B
+	C      C
+}
+
+// Query1 returns a row for every component of type A
+// whose entity is not excluded by the options.
+func Query1[A any, PA ComponentPointer[A]](s *ECS, opts ...QueryOption) []Row1[A] {
+	return slices.Collect(IterQuery1[A, PA](s, opts...))
+}
+
+// Query2 returns a row for every component of type A whose entity also has a component of type B.
+// The row holds the first component of type B of the entity.
+// Entities that are excluded by the options are skipped.
+func Query2[A, B any, PA ComponentPointer[A], PB ComponentPointer[B]](s *ECS, opts ...QueryOption) []Row2[A, B] {
+	return slices.Collect(IterQuery2[A, B, PA, PB](s, opts...))
+}
+
+// Query3 returns a row for every component of type A whose entity also has components of type B and C.
+// The row holds the first component of type B and C of the entity.
+// Entities that are excluded by the options are skipped.
+func Query3[
+	A, B, C any,
+	PA ComponentPointer[A],
+	PB ComponentPointer[B],
+	PC ComponentPointer[C],
+](s *ECS, opts ...QueryOption) []Row3[A, B, C] {
+	return slices.Collect(IterQuery3[A, B, C, PA, PB, PC](s, opts...))
+}
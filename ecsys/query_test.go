@@ -0,0 +1,88 @@
+package ecsys_test
+
+import (
+	"testing"
+
+	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQuery(t *testing.T) {
+	ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+
+	// e1 has a position and a velocity.
+	e1, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if _, err = ecsys.Add(ecs, *velocity.New(e1, point.New(1, 0))); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// e2 has a position, a velocity and a controllable.
+	e2, err := ecs.CreateEntity(ecs.Root(), point.New(2, 2))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if _, err = ecsys.Add(ecs, *velocity.New(e2, point.New(0, 1))); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err = ecsys.Add(ecs, *controllable.New(e2)); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// e3 only has a position.
+	if _, err = ecs.CreateEntity(ecs.Root(), point.New(3, 3)); err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+
+	t.Run("Query1 should return all components of a type", func(t *testing.T) {
+		if l := len(ecsys.Query1[position.Position](ecs)); l != 3 {
+			t.Errorf("Query1() len = %d, want 3", l)
+		}
+	})
+
+	t.Run("Query2 should return entities that have both components", func(t *testing.T) {
+		rows := ecsys.Query2[position.Position, velocity.Velocity](ecs)
+		var got []entity.Entity
+		for _, r := range rows {
+			if r.A.Entity() != r.Entity || r.B.Entity() != r.Entity {
+				t.Errorf("row components do not belong to entity %d", r.Entity)
+			}
+			got = append(got, r.Entity)
+		}
+		if diff := cmp.Diff([]entity.Entity{e1, e2}, got); diff != "" {
+			t.Errorf("Query2() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Query2 should skip excluded entities", func(t *testing.T) {
+		rows := ecsys.Query2[position.Position, velocity.Velocity](ecs, ecsys.Without[controllable.Controllable]())
+		if len(rows) != 1 || rows[0].Entity != e1 {
+			t.Errorf("Query2() = %v, want only entity %d", rows, e1)
+		}
+	})
+
+	t.Run("Query3 should return entities that have all three components", func(t *testing.T) {
+		rows := ecsys.Query3[controllable.Controllable, position.Position, velocity.Velocity](ecs)
+		if len(rows) != 1 || rows[0].Entity != e2 {
+			t.Fatalf("Query3() = %v, want only entity %d", rows, e2)
+		}
+		if rows[0].B.Point != point.New(2, 2) {
+			t.Errorf("Query3() position = %v, want (2, 2)", rows[0].B.Point)
+		}
+	})
+
+	t.Run("Query2 should return nothing if no entity has both components", func(t *testing.T) {
+		if rows := ecsys.Query2[hitbox.Hitbox, position.Position](ecs); len(rows) != 0 {
+			t.Errorf("Query2() = %v, want none", rows)
+		}
+	})
+}
@@ -0,0 +1,328 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+)
+
+// ErrComponentNotRegistered is returned when a component type has not been registered.
+var ErrComponentNotRegistered = errors.New("component type not registered")
+
+// ComponentPointer is satisfied by a pointer to C that implements component.Component.
+// It allows the generic ECS functions to accept and return component values
+// while the stores hold pointers.
+type ComponentPointer[C any] interface {
+	*C
+	component.Component
+}
+
+// Registration describes a component type to the ECS.
+// A component package registers its type once, after which the generic
+// Add, Get, Update, Delete, List and All functions work for it and
+// DeleteEntity cleans up its store.
+type Registration[T component.Component] struct {
+	// Type is the component type.
+	Type component.Type
+	// UniquePerEntity enforces that an entity has at most one component of this type.
+	UniquePerEntity bool
+	// Persistent marks the components of this type to be saved and loaded by the persistence system.
+	Persistent bool
+	// NewStore creates the store for the component type. When nil a MemStore is used.
+	NewStore func(uniquePerEntity bool) Store[T]
+	// CreatedEvent, UpdatedEvent and DeletedEvent create the events that are published
+	// when a component is added, updated or deleted. They are optional.
+	CreatedEvent func(T) event.Event
+	UpdatedEvent func(T) event.Event
+	DeletedEvent func(T) event.Event
+	// Clone returns a deep copy of a component. It is used by snapshots and observers and only needed
+	// for components that hold slices, maps or pointers. When nil the struct is copied.
+	Clone func(T) T
+	// Indexes are the secondary indexes on the component type, see Lookup.
+	Indexes []Index[T]
+	// AfterAdd, AfterUpdate and AfterDelete are called after the store has been changed
+	// and the event has been published, for example to keep derived state in sync. They are
+	// optional. Inside a transaction they receive its handle, changes they make through it are
+	// part of the transaction.
+	AfterAdd    func(*ECS, T) error
+	AfterUpdate func(*ECS, T) error
+	AfterDelete func(*ECS, T) error
+}
+
+// registered is the type-erased form of a Registration.
+type registered interface {
+	componentType() component.Type
+	uniquePerEntity() bool
+	persistent() bool
+	newComponent() component.Component
+	insert(s *Stores, c component.Component) error
+	newStore() any
+	deleteByEntity(s *ECS, e entity.Entity) error
+	hasEntity(s *Stores, e entity.Entity) bool
+	entities(s *Stores) []entity.Entity
+	addAny(s *ECS, c component.Component) error
+	updateAny(s *ECS, c component.Component) error
+	deleteAny(s *ECS, c component.Component) error
+	publishUpdated(s *ECS, id uint) error
+	snapshot(s *Stores) []component.Component
+	restore(s *Stores, components []component.Component) error
+	indexes() []*index
+	list(s *Stores) []component.Component
+}
+
+// registry holds all registered component types in registration order.
+var registry = struct {
+	mu       sync.RWMutex
+	types    []registered
+	byType   map[component.Type]registered
+	byGoType map[reflect.Type]registered
+}{
+	byType:   make(map[component.Type]registered),
+	byGoType: make(map[reflect.Type]registered),
+}
+
+// Register registers a component type with the ECS. It is meant to be called
+// from an init function and panics if the type is registered twice.
+// Stores created by NewStores after the call will contain a store for the type.
+func Register[T component.Component](r Registration[T]) {
+	if r.Type == "" {
+		panic("ecsys: Register called with an empty component type")
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	goType := reflect.TypeFor[T]()
+	if _, ok := registry.byType[r.Type]; ok {
+		panic(fmt.Sprintf("ecsys: Register called twice for component type %q", r.Type))
+	}
+	if _, ok := registry.byGoType[goType]; ok {
+		panic(fmt.Sprintf("ecsys: Register called twice for %v", goType))
+	}
+	for _, i := range r.Indexes {
+		for _, other := range registry.types {
+			for _, o := range other.indexes() {
+				if o.name == i.Name {
+					panic(fmt.Sprintf("ecsys: index %q is registered twice", i.Name))
+				}
+			}
+		}
+	}
+	registry.types = append(registry.types, r)
+	registry.byType[r.Type] = r
+	registry.byGoType[goType] = r
+	if types, ok := r.eventTypes(); ok {
+		component.RegisterEventTypes(r.Type, types...)
+	}
+}
+
+// RegisteredTypes returns all registered component types in registration order.
+func RegisteredTypes() []component.Type {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	types := make([]component.Type, len(registry.types))
+	for i, r := range registry.types {
+		types[i] = r.componentType()
+	}
+	return types
+}
+
+// PersistentTypes returns the component types that are registered as persistent, in registration order.
+func PersistentTypes() []component.Type {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	var types []component.Type
+	for _, r := range registry.types {
+		if r.persistent() {
+			types = append(types, r.componentType())
+		}
+	}
+	return types
+}
+
+// ComponentFactory returns a function that creates an empty component of a registered type,
+// for example to decode a saved component into.
+func ComponentFactory(t component.Type) (func() component.Component, error) {
+	registry.mu.RLock()
+	r, ok := registry.byType[t]
+	registry.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrComponentNotRegistered, t)
+	}
+	return r.newComponent, nil
+}
+
+// Insert adds a component of a registered type directly to its store, keeping its ID.
+// No events are published and no hooks are called, it is meant for loading saved components
+// into new stores. The entity of the component must be marked as alive with SyncEntities.
+func (s *Stores) Insert(c component.Component) error {
+	r, err := lookupComponent(c)
+	if err != nil {
+		return err
+	}
+	return r.insert(s, c)
+}
+
+// registrations returns a copy of all registrations in registration order.
+func registrations() []registered {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	r := make([]registered, len(registry.types))
+	copy(r, registry.types)
+	return r
+}
+
+// lookup returns the registration of component type T.
+func lookup[T component.Component]() (Registration[T], error) {
+	goType := reflect.TypeFor[T]()
+	registry.mu.RLock()
+	r, ok := registry.byGoType[goType]
+	registry.mu.RUnlock()
+	if !ok {
+		return Registration[T]{}, fmt.Errorf("%w: %v", ErrComponentNotRegistered, goType)
+	}
+	reg, ok := r.(Registration[T])
+	if !ok {
+		return Registration[T]{}, fmt.Errorf("expected registration of %v, got %T", goType, r)
+	}
+	return reg, nil
+}
+
+// lookupComponent returns the registration of the dynamic type of the component.
+func lookupComponent(c component.Component) (registered, error) {
+	goType := reflect.TypeOf(c)
+	registry.mu.RLock()
+	r, ok := registry.byGoType[goType]
+	registry.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrComponentNotRegistered, goType)
+	}
+	return r, nil
+}
+
+func (r Registration[T]) componentType() component.Type { return r.Type }
+func (r Registration[T]) uniquePerEntity() bool         { return r.UniquePerEntity }
+func (r Registration[T]) persistent() bool              { return r.Persistent }
+
+// newComponent returns a new zero value component of type T.
+func (r Registration[T]) newComponent() component.Component { return r.empty() }
+
+// empty returns a new zero value component of type T.
+func (r Registration[T]) empty() T {
+	if t := reflect.TypeFor[T](); t.Kind() == reflect.Pointer {
+		return reflect.New(t.Elem()).Interface().(T) //nolint: forcetypeassert // a new *C is a T
+	}
+	var zero T
+	return zero
+}
+
+// eventTypes returns the types of the events that are published for components of type T, taken from
+// the events the registration creates for an empty component. It is false if they cannot be created.
+func (r Registration[T]) eventTypes() (types []string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			types, ok = nil, false
+		}
+	}()
+	c := r.empty()
+	for _, newEvent := range []func(T) event.Event{r.CreatedEvent, r.UpdatedEvent, r.DeletedEvent} {
+		if newEvent != nil {
+			types = append(types, newEvent(c).Event())
+		}
+	}
+	return types, true
+}
+
+// insert adds a component that is known to be of type T to its store.
+func (r Registration[T]) insert(s *Stores, c component.Component) error {
+	comp, ok := c.(T)
+	if !ok {
+		return fmt.Errorf("expected component of type %v, got %T", reflect.TypeFor[T](), c)
+	}
+	store, err := StoreFor[T](s)
+	if err != nil {
+		return err
+	}
+	_, err = store.Add(comp)
+	return err
+}
+
+func (r Registration[T]) newStore() any {
+	if r.NewStore != nil {
+		return r.NewStore(r.UniquePerEntity)
+	}
+	return NewMemStore[T](r.UniquePerEntity)
+}
+
+// deleteByEntity deletes all components of type T of the entity, publishing an event for each.
+// It returns ErrEntityNotFound if the entity has no components of type T.
+func (r Registration[T]) deleteByEntity(s *ECS, e entity.Entity) error {
+	store, err := StoreFor[T](s.stores)
+	if err != nil {
+		return err
+	}
+	comps := store.ListByEntity(e)
+	if len(comps) == 0 {
+		return ErrEntityNotFound
+	}
+	for _, c := range comps {
+		if err = deleteComponent(s, r, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasEntity reports whether the entity has a component of type T.
+func (r Registration[T]) hasEntity(s *Stores, e entity.Entity) bool {
+	store, err := StoreFor[T](s)
+	if err != nil {
+		return false
+	}
+	_, err = store.First(e)
+	return err == nil
+}
+
+// entities returns the entities that have a component of type T.
+func (r Registration[T]) entities(s *Stores) []entity.Entity {
+	store, err := StoreFor[T](s)
+	if err != nil {
+		return nil
+	}
+	var entities []entity.Entity
+	for _, c := range store.List() {
+		entities = append(entities, c.Entity())
+	}
+	return entities
+}
+
+// addAny adds a component that is known to be of type T.
+func (r Registration[T]) addAny(s *ECS, c component.Component) error {
+	comp, ok := c.(T)
+	if !ok {
+		return fmt.Errorf("expected component of type %v, got %T", reflect.TypeFor[T](), c)
+	}
+	_, err := addComponent(s, r, comp)
+	return err
+}
+
+// updateAny updates a component that is known to be of type T.
+func (r Registration[T]) updateAny(s *ECS, c component.Component) error {
+	comp, ok := c.(T)
+	if !ok {
+		return fmt.Errorf("expected component of type %v, got %T", reflect.TypeFor[T](), c)
+	}
+	return updateComponent(s, r, comp)
+}
+
+// deleteAny deletes a component that is known to be of type T.
+func (r Registration[T]) deleteAny(s *ECS, c component.Component) error {
+	comp, ok := c.(T)
+	if !ok {
+		return fmt.Errorf("expected component of type %v, got %T", reflect.TypeFor[T](), c)
+	}
+	return deleteComponent(s, r, comp)
+}
@@ -0,0 +1,236 @@
+package ecsys_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+type UnregisteredComponent struct {
+	I uint
+	E entity.Entity
+}
+
+func (u *UnregisteredComponent) ID() uint              { return u.I }
+func (u *UnregisteredComponent) SetID(i uint)          { u.I = i }
+func (u *UnregisteredComponent) Type() component.Type  { return "unregistered" }
+func (u *UnregisteredComponent) Entity() entity.Entity { return u.E }
+
+// HookedComponent is a component type registered with hooks, its hooks record their calls in hookCalls.
+type HookedComponent struct {
+	I uint
+	E entity.Entity
+}
+
+func (h *HookedComponent) ID() uint              { return h.I }
+func (h *HookedComponent) SetID(i uint)          { h.I = i }
+func (h *HookedComponent) Type() component.Type  { return "hooked" }
+func (h *HookedComponent) Entity() entity.Entity { return h.E }
+
+var hookCalls []string
+
+func init() {
+	ecsys.Register(ecsys.Registration[*TestComponent]{
+		Type:            "test",
+		UniquePerEntity: false,
+	})
+	hook := func(name string) func(*ecsys.ECS, *HookedComponent) error {
+		return func(_ *ecsys.ECS, h *HookedComponent) error {
+			hookCalls = append(hookCalls, name)
+			return nil
+		}
+	}
+	ecsys.Register(ecsys.Registration[*HookedComponent]{
+		Type:        "hooked",
+		AfterAdd:    hook("add"),
+		AfterUpdate: hook("update"),
+		AfterDelete: hook("delete"),
+	})
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("should panic if a type is registered twice", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Register() should panic")
+			}
+		}()
+		ecsys.Register(ecsys.Registration[*TestComponent]{Type: "test"})
+	})
+
+	t.Run("should call the hooks of a registered type", func(t *testing.T) {
+		hookCalls = nil
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		c := HookedComponent{E: 1}
+		var err error
+		if c.I, err = ecsys.Add(ecs, c); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err = ecsys.Update(ecs, c); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if err = ecsys.Delete(ecs, c); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if diff := cmp.Diff([]string{"add", "update", "delete"}, hookCalls); diff != "" {
+			t.Errorf("hook calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should list registered types", func(t *testing.T) {
+		types := ecsys.RegisteredTypes()
+		for _, want := range []component.Type{velocity.Type, hitbox.Type, "test"} {
+			found := false
+			for _, got := range types {
+				found = found || got == want
+			}
+			if !found {
+				t.Errorf("RegisteredTypes() = %v, missing %q", types, want)
+			}
+		}
+	})
+
+	t.Run("should list persistent types", func(t *testing.T) {
+		types := ecsys.PersistentTypes()
+		if !slices.Contains(types, velocity.Type) {
+			t.Errorf("PersistentTypes() = %v, missing %q", types, velocity.Type)
+		}
+		if slices.Contains(types, hitbox.Type) {
+			t.Errorf("PersistentTypes() = %v, should not contain %q", types, hitbox.Type)
+		}
+	})
+
+	t.Run("should create and insert empty components", func(t *testing.T) {
+		factory, err := ecsys.ComponentFactory(velocity.Type)
+		if err != nil {
+			t.Fatalf("ComponentFactory() error = %v", err)
+		}
+		c, ok := factory().(*velocity.Velocity)
+		if !ok {
+			t.Fatalf("factory() = %T, want *velocity.Velocity", c)
+		}
+		if diff := cmp.Diff(&velocity.Velocity{}, c); diff != "" {
+			t.Errorf("factory() mismatch (-want +got):\n%s", diff)
+		}
+
+		stores := ecsys.NewStores()
+		c.I, c.E = 7, entity.Entity(3)
+		if err = stores.Insert(c); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+		ecs := ecsys.New(event.NewBus(), stores)
+		if err = ecs.SyncEntities(); err != nil {
+			t.Fatalf("SyncEntities() error = %v", err)
+		}
+		got, err := ecsys.Get[velocity.Velocity](ecs, c.E)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got.ID() != 7 {
+			t.Errorf("ID() = %d, want 7", got.ID())
+		}
+		if _, err = ecsys.ComponentFactory("unregistered"); !errors.Is(err, ecsys.ErrComponentNotRegistered) {
+			t.Errorf("ComponentFactory() error = %v, want %v", err, ecsys.ErrComponentNotRegistered)
+		}
+	})
+}
+
+func TestGeneric(t *testing.T) {
+	t.Run("should add, get, update, list and delete a registered component", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e, err := ecs.CreateEmptyEntity()
+		if err != nil {
+			t.Fatalf("CreateEmptyEntity() error = %v", err)
+		}
+
+		id, err := ecsys.Add(ecs, TestComponent{E: e, Tag: "a"})
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		if _, err = ecsys.Add(ecs, TestComponent{E: e, Tag: "b"}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		c, err := ecsys.Get[TestComponent](ecs, e)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if c.I != id {
+			t.Errorf("Get() id = %d, want %d", c.I, id)
+		}
+
+		c.Tag = "updated"
+		if err = ecsys.Update(ecs, c); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		got := ecsys.List[TestComponent](ecs, e)
+		expect := []TestComponent{
+			{I: 1, E: e, Tag: "updated"},
+			{I: 2, E: e, Tag: "b"},
+		}
+		if diff := cmp.Diff(expect, got); diff != "" {
+			t.Errorf("List() mismatch (-want +got):\n%s", diff)
+		}
+
+		if err = ecsys.Delete(ecs, c); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+
+		if l := len(ecsys.All[TestComponent](ecs)); l != 1 {
+			t.Errorf("All() len = %d, want 1", l)
+		}
+	})
+
+	t.Run("should return an error for unregistered components", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		_, err := ecsys.Add(ecs, UnregisteredComponent{E: 1})
+		if !errors.Is(err, ecsys.ErrComponentNotRegistered) {
+			t.Errorf("Add() error = %v, want ErrComponentNotRegistered", err)
+		}
+	})
+}
+
+func TestECS_DeleteEntity_AllRegisteredStores(t *testing.T) {
+	t.Run("should delete components of every registered type", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+
+		if _, err = ecsys.Add(ecs, *velocity.New(e, point.New(1, 1))); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *hitbox.New(e, "main", 1, 1, point.Zero())); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, TestComponent{E: e}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		if err = ecs.DeleteEntity(e); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+
+		if _, err = ecsys.Get[velocity.Velocity](ecs, e); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("Get() error = %v, want ErrEntityNotFound", err)
+		}
+		if l := len(ecsys.List[hitbox.Hitbox](ecs, e)); l != 0 {
+			t.Errorf("List() len = %d, want 0", l)
+		}
+		if l := len(ecsys.List[TestComponent](ecs, e)); l != 0 {
+			t.Errorf("List() len = %d, want 0", l)
+		}
+	})
+}
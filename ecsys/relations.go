@@ -0,0 +1,71 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dwethmar/vork/component/relation"
+	"github.com/dwethmar/vork/entity"
+)
+
+// Relate relates the source to the target with a relation of the kind.
+// Relating entities that are already related by the kind does nothing.
+func (s *ECS) Relate(kind relation.Kind, source, target entity.Entity) error {
+	if !s.Alive(target) {
+		return fmt.Errorf("could not relate %v to %v: %w", source, target, ErrEntityNotFound)
+	}
+	if _, err := Add(s, *relation.New(kind, source, target)); err != nil {
+		if errors.Is(err, ErrIndexValueTaken) {
+			return nil
+		}
+		return fmt.Errorf("could not relate %v to %v: %w", source, target, err)
+	}
+	return nil
+}
+
+// Unrelate removes the relation of the kind between the source and the target, if any.
+func (s *ECS) Unrelate(kind relation.Kind, source, target entity.Entity) error {
+	for _, r := range Lookup[relation.Relation](s, relation.KeyIndex, relation.Key(kind, source, target)) {
+		if err := Delete(s, r); err != nil {
+			return fmt.Errorf("could not unrelate %v from %v: %w", source, target, err)
+		}
+	}
+	return nil
+}
+
+// Related returns the targets of the relations of the kind of the source, in the order they were related.
+func (s *ECS) Related(source entity.Entity, kind relation.Kind) []entity.Entity {
+	relations := LookupByEntity[relation.Relation](s, source, relation.SourceIndex, string(kind))
+	targets := make([]entity.Entity, len(relations))
+	for i, r := range relations {
+		targets[i] = r.Target
+	}
+	return targets
+}
+
+// RelatedTo returns the sources of the relations of the kind to the target, ordered by entity.
+func (s *ECS) RelatedTo(target entity.Entity, kind relation.Kind) []entity.Entity {
+	return s.LookupEntities(relation.TargetIndex, relation.TargetKey(kind, target))
+}
+
+// Relations returns all relations of the source.
+func (s *ECS) Relations(source entity.Entity) []relation.Relation {
+	return List[relation.Relation](s, source)
+}
+
+// deleteRelationsTo deletes the relations of any kind to the target.
+// Relations from an entity are deleted with the other components of the entity.
+func (s *ECS) deleteRelationsTo(target entity.Entity) error {
+	for _, r := range Lookup[relation.Relation](s, relation.TargetIndex, targetValue(target)) {
+		if err := Delete(s, r); err != nil {
+			return fmt.Errorf("could not delete relation %d to %v: %w", r.ID(), target, err)
+		}
+	}
+	return nil
+}
+
+// targetValue returns the value of the target of any relation in the TargetIndex.
+func targetValue(target entity.Entity) string {
+	return strconv.FormatUint(uint64(target), 10)
+}
@@ -0,0 +1,111 @@
+package ecsys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwethmar/vork/component/relation"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestECS_Relate(t *testing.T) {
+	t.Run("should look up relations forward and in reverse", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 4)
+		for _, r := range []struct {
+			kind           relation.Kind
+			source, target entity.Entity
+		}{
+			{relation.Targets, es[0], es[2]},
+			{relation.Targets, es[0], es[1]},
+			{relation.Follows, es[0], es[3]},
+			{relation.Targets, es[3], es[1]},
+			{relation.Targets, es[0], es[2]}, // already related
+		} {
+			if err := ecs.Relate(r.kind, r.source, r.target); err != nil {
+				t.Fatalf("Relate() error = %v", err)
+			}
+		}
+
+		if diff := cmp.Diff([]entity.Entity{es[2], es[1]}, ecs.Related(es[0], relation.Targets)); diff != "" {
+			t.Errorf("Related() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]entity.Entity{es[0], es[3]}, ecs.RelatedTo(es[1], relation.Targets)); diff != "" {
+			t.Errorf("RelatedTo() mismatch (-want +got):\n%s", diff)
+		}
+		if got := ecs.RelatedTo(es[3], relation.Targets); len(got) != 0 {
+			t.Errorf("RelatedTo() = %v, want none", got)
+		}
+		if l := len(ecs.Relations(es[0])); l != 3 {
+			t.Errorf("Relations() len = %d, want 3", l)
+		}
+	})
+
+	t.Run("should not relate to a deleted entity", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 2)
+		if err := ecs.DeleteEntity(es[1]); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if err := ecs.Relate(relation.OwnedBy, es[0], es[1]); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("Relate() error = %v, want %v", err, ecsys.ErrEntityNotFound)
+		}
+	})
+}
+
+func TestECS_Unrelate(t *testing.T) {
+	t.Run("should remove the relation", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		es := newEntities(t, ecs, 2)
+		if err := ecs.Relate(relation.OwnedBy, es[0], es[1]); err != nil {
+			t.Fatalf("Relate() error = %v", err)
+		}
+		if err := ecs.Unrelate(relation.OwnedBy, es[0], es[1]); err != nil {
+			t.Fatalf("Unrelate() error = %v", err)
+		}
+		if got := ecs.Related(es[0], relation.OwnedBy); len(got) != 0 {
+			t.Errorf("Related() = %v, want none", got)
+		}
+		if err := ecs.Relate(relation.OwnedBy, es[0], es[1]); err != nil {
+			t.Fatalf("Relate() error = %v", err)
+		}
+	})
+}
+
+func TestECS_DeleteEntity_Relations(t *testing.T) {
+	t.Run("should delete the relations from and to the entity", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		deleted := 0
+		bus.Subscribe(event.MatchAny(relation.DeletedEventType), func(event.Event) error {
+			deleted++
+			return nil
+		})
+		es := newEntities(t, ecs, 3)
+		if err := ecs.Relate(relation.Follows, es[0], es[1]); err != nil {
+			t.Fatalf("Relate() error = %v", err)
+		}
+		if err := ecs.Relate(relation.Targets, es[1], es[2]); err != nil {
+			t.Fatalf("Relate() error = %v", err)
+		}
+		if err := ecs.Relate(relation.Targets, es[0], es[2]); err != nil {
+			t.Fatalf("Relate() error = %v", err)
+		}
+
+		if err := ecs.DeleteEntity(es[1]); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if got := ecs.Related(es[0], relation.Follows); len(got) != 0 {
+			t.Errorf("Related() = %v, want none", got)
+		}
+		if diff := cmp.Diff([]entity.Entity{es[0]}, ecs.RelatedTo(es[2], relation.Targets)); diff != "" {
+			t.Errorf("RelatedTo() mismatch (-want +got):\n%s", diff)
+		}
+		if deleted != 2 {
+			t.Errorf("deleted events = %d, want 2", deleted)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package ecsys
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/resource"
+)
+
+// ErrResourceNotFound is returned when a resource is not in the ECS.
+var ErrResourceNotFound = errors.New("resource not found")
+
+// resourceEntry holds the value of a resource and its options.
+type resourceEntry struct {
+	value      resource.Resource
+	persistent bool
+	owner      *staging // transaction that last wrote the resource, see owners
+}
+
+// resources holds the global resources by type.
+type resources struct {
+	mu      sync.RWMutex
+	entries map[resource.Type]resourceEntry
+}
+
+func newResources() *resources {
+	return &resources{entries: make(map[resource.Type]resourceEntry)}
+}
+
+// ResourceOption configures a resource when it is inserted.
+type ResourceOption func(*resourceEntry)
+
+// Persistent marks the resource to be saved by the persistence system.
+func Persistent() ResourceOption {
+	return func(e *resourceEntry) { e.persistent = true }
+}
+
+// InsertResource inserts the resource, or replaces the value of the resource of the same type.
+// Options that are not given keep their value when the resource is replaced.
+// A change event is published for the resource.
+func (s *ECS) InsertResource(r resource.Resource, opts ...ResourceOption) error {
+	s.resources.mu.Lock()
+	old, exists := s.resources.entries[r.Type()]
+	entry := old
+	entry.value = r
+	entry.owner = s.staging
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	s.resources.entries[r.Type()] = entry
+	s.resources.mu.Unlock()
+
+	s.recordUndo(func() error {
+		s.resources.mu.Lock()
+		defer s.resources.mu.Unlock()
+		if s.resources.entries[r.Type()].owner != s.staging {
+			return nil
+		}
+		if exists {
+			s.resources.entries[r.Type()] = old
+		} else {
+			delete(s.resources.entries, r.Type())
+		}
+		return nil
+	})
+	if err := s.publish(resource.NewChangedEvent(r, entry.persistent)); err != nil {
+		return fmt.Errorf("could not publish resource event: %w", err)
+	}
+	return nil
+}
+
+// GetResource returns the resource of type R.
+func GetResource[R resource.Resource](s *ECS) (R, error) {
+	var zero R
+	s.resources.mu.RLock()
+	entry, ok := s.resources.entries[zero.Type()]
+	s.resources.mu.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrResourceNotFound, zero.Type())
+	}
+	r, ok := entry.value.(R)
+	if !ok {
+		return zero, fmt.Errorf("resource %s is %T, not %T", zero.Type(), entry.value, zero)
+	}
+	return r, nil
+}
+
+// UpdateResource calls fn with the value of the resource of type R. If fn returns true
+// the value is stored and a change event is published. Concurrent updates of the same
+// resource do not overwrite each other. fn must not access the resources of the ECS.
+func UpdateResource[R resource.Resource](s *ECS, fn func(*R) bool) error {
+	var zero R
+	s.resources.mu.Lock()
+	old, ok := s.resources.entries[zero.Type()]
+	if !ok {
+		s.resources.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, zero.Type())
+	}
+	r, ok := old.value.(R)
+	if !ok {
+		s.resources.mu.Unlock()
+		return fmt.Errorf("resource %s is %T, not %T", zero.Type(), old.value, zero)
+	}
+	if !fn(&r) {
+		s.resources.mu.Unlock()
+		return nil
+	}
+	entry := old
+	entry.value = r
+	entry.owner = s.staging
+	s.resources.entries[zero.Type()] = entry
+	s.resources.mu.Unlock()
+
+	s.recordUndo(func() error {
+		s.resources.mu.Lock()
+		defer s.resources.mu.Unlock()
+		if s.resources.entries[zero.Type()].owner != s.staging {
+			return nil
+		}
+		s.resources.entries[zero.Type()] = old
+		return nil
+	})
+	if err := s.publish(resource.NewChangedEvent(r, entry.persistent)); err != nil {
+		return fmt.Errorf("could not publish resource event: %w", err)
+	}
+	return nil
+}
+
+// Resources returns all resources, ordered by type.
+func (s *ECS) Resources() []resource.Resource {
+	s.resources.mu.RLock()
+	defer s.resources.mu.RUnlock()
+	types := slices.Sorted(maps.Keys(s.resources.entries))
+	rs := make([]resource.Resource, len(types))
+	for i, t := range types {
+		rs[i] = s.resources.entries[t].value
+	}
+	return rs
+}
+
+// PersistentResources returns the resources that are saved by the persistence system, ordered by type.
+func (s *ECS) PersistentResources() []resource.Resource {
+	s.resources.mu.RLock()
+	defer s.resources.mu.RUnlock()
+	var rs []resource.Resource
+	for _, e := range s.resources.entries {
+		if e.persistent {
+			rs = append(rs, e.value)
+		}
+	}
+	slices.SortFunc(rs, func(a, b resource.Resource) int { return cmp.Compare(a.Type(), b.Type()) })
+	return rs
+}
@@ -0,0 +1,129 @@
+package ecsys_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/resource"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestECS_InsertResource(t *testing.T) {
+	t.Run("should insert and replace a resource and publish change events", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		var events []resource.Event
+		bus.Subscribe(event.MatchAny(resource.ChangedEventType(resource.CameraType)), func(e event.Event) error {
+			events = append(events, e.(resource.Event))
+			return nil
+		})
+
+		if err := ecs.InsertResource(resource.NewCamera(), ecsys.Persistent()); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+		want := resource.Camera{X: 1, Y: 2, Zoom: 2}
+		if err := ecs.InsertResource(want); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+
+		got, err := ecsys.GetResource[resource.Camera](ecs)
+		if err != nil {
+			t.Fatalf("GetResource[Camera]() error = %v", err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("GetResource[Camera]() mismatch (-want +got):\n%s", diff)
+		}
+		if len(events) != 2 {
+			t.Fatalf("events = %d, want 2", len(events))
+		}
+		if !events[1].Persistent() {
+			t.Error("resource should stay persistent when it is replaced")
+		}
+		if diff := cmp.Diff([]resource.Resource{want}, ecs.PersistentResources()); diff != "" {
+			t.Errorf("PersistentResources() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestGetResource(t *testing.T) {
+	t.Run("should return ErrResourceNotFound if the resource is not inserted", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		if _, err := ecsys.GetResource[resource.Time](ecs); !errors.Is(err, ecsys.ErrResourceNotFound) {
+			t.Errorf("GetResource() error = %v, want %v", err, ecsys.ErrResourceNotFound)
+		}
+	})
+}
+
+func TestUpdateResource(t *testing.T) {
+	t.Run("should only store and publish the resource if fn returns true", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		published := 0
+		bus.Subscribe(event.MatchAny(resource.ChangedEventType(resource.TimeType)), func(event.Event) error {
+			published++
+			return nil
+		})
+		if err := ecs.InsertResource(resource.Time{}); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+
+		if err := ecsys.UpdateResource(ecs, func(t *resource.Time) bool {
+			t.Frame = 5
+			return false
+		}); err != nil {
+			t.Fatalf("UpdateResource() error = %v", err)
+		}
+		if err := ecsys.UpdateResource(ecs, func(t *resource.Time) bool {
+			t.Advance(10)
+			return true
+		}); err != nil {
+			t.Fatalf("UpdateResource() error = %v", err)
+		}
+
+		got, err := ecsys.GetResource[resource.Time](ecs)
+		if err != nil {
+			t.Fatalf("GetResource[Time]() error = %v", err)
+		}
+		if diff := cmp.Diff(resource.Time{Frame: 1, Delta: 10, Elapsed: 10}, got); diff != "" {
+			t.Errorf("GetResource[Time]() mismatch (-want +got):\n%s", diff)
+		}
+		if published != 2 {
+			t.Errorf("published = %d, want 2", published)
+		}
+	})
+
+	t.Run("should roll back the resource if the transaction fails", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		if err := ecs.InsertResource(resource.GameSettings{Friction: 4}); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+		wantErr := errors.New("failed")
+		err := ecs.Tx(func(tx *ecsys.ECS) error {
+			if err := ecsys.UpdateResource(tx, func(s *resource.GameSettings) bool {
+				s.Friction = 2
+				return true
+			}); err != nil {
+				return err
+			}
+			if err := tx.InsertResource(resource.InputState{}); err != nil {
+				return err
+			}
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Tx() error = %v, want %v", err, wantErr)
+		}
+		got, err := ecsys.GetResource[resource.GameSettings](ecs)
+		if err != nil {
+			t.Fatalf("GetResource[GameSettings]() error = %v", err)
+		}
+		if got.Friction != 4 {
+			t.Errorf("Friction = %d, want 4", got.Friction)
+		}
+		if _, err = ecsys.GetResource[resource.InputState](ecs); !errors.Is(err, ecsys.ErrResourceNotFound) {
+			t.Errorf("GetResource[InputState]() error = %v, want %v", err, ecsys.ErrResourceNotFound)
+		}
+	})
+}
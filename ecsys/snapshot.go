@@ -0,0 +1,124 @@
+package ecsys
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/resource"
+)
+
+// Snapshot is a deep copy of the state of an ECS: the components of every registered type,
+// the entity table, the persistent resources and, through the positions, the hierarchy.
+// Resources that are not persistent, such as the time and the input state, describe the
+// running session and are not part of a snapshot.
+// A snapshot can be restored any number of times, into the same or another ECS.
+type Snapshot struct {
+	entities   *entities
+	components map[component.Type][]component.Component // deep copies of the components per type
+	resources  []resource.Resource                      // persistent resources, resources are values
+}
+
+// Len returns the number of components in the snapshot of the component type.
+func (s *Snapshot) Len(t component.Type) int {
+	return len(s.components[t])
+}
+
+// Snapshot takes a deep copy of the state of the ECS.
+// Unlike persistence it covers all registered component types.
+func (s *ECS) Snapshot() *Snapshot {
+	s.mu.RLock()
+	snap := &Snapshot{
+		entities:   s.entities.clone(),
+		components: make(map[component.Type][]component.Component, len(s.stores.types)),
+	}
+	s.mu.RUnlock()
+	snap.resources = s.PersistentResources()
+	for _, r := range s.stores.types {
+		snap.components[r.componentType()] = r.snapshot(s.stores)
+	}
+	return snap
+}
+
+// Restore replaces the state of the ECS with the snapshot and rebuilds the hierarchy.
+// Component types in the snapshot that have no store in the ECS are skipped and stores
+// that are not in the snapshot are emptied. The persistent resources in the snapshot
+// replace the resources of the same type, other resources keep their value. No events are published, handlers that keep
+// state derived from the components have to be re-initialized.
+func (s *ECS) Restore(snap *Snapshot) error {
+	if s.isStaging() {
+		return fmt.Errorf("could not restore snapshot: %w", ErrStagingInProgress)
+	}
+	for _, r := range s.stores.types {
+		if err := r.restore(s.stores, snap.components[r.componentType()]); err != nil {
+			return fmt.Errorf("could not restore %s components: %w", r.componentType(), err)
+		}
+	}
+	s.mu.Lock()
+	s.entities = snap.entities.clone()
+	s.mu.Unlock()
+	s.resources.mu.Lock()
+	for _, r := range snap.resources {
+		s.resources.entries[r.Type()] = resourceEntry{value: r, persistent: true}
+	}
+	s.resources.mu.Unlock()
+	s.changes.reset()
+	if err := s.BuildHierarchy(); err != nil {
+		return fmt.Errorf("could not restore hierarchy: %w", err)
+	}
+	if err := s.BuildIndexes(); err != nil {
+		return fmt.Errorf("could not restore indexes: %w", err)
+	}
+	return nil
+}
+
+// clone returns a deep copy of the component.
+func (r Registration[T]) clone(c T) T {
+	if r.Clone != nil {
+		return r.Clone(c)
+	}
+	v := reflect.ValueOf(c).Elem()
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	return cp.Interface().(T) //nolint: forcetypeassert // cp has the type of c
+}
+
+// snapshot returns deep copies of all components of type T.
+func (r Registration[T]) snapshot(s *Stores) []component.Component {
+	store, err := StoreFor[T](s)
+	if err != nil {
+		return nil
+	}
+	var comps []component.Component
+	for c := range store.Iter() {
+		comps = append(comps, r.clone(c))
+	}
+	return comps
+}
+
+// restore replaces all components of type T with copies of the given components.
+func (r Registration[T]) restore(s *Stores, components []component.Component) error {
+	store, err := StoreFor[T](s)
+	if err != nil {
+		return err
+	}
+	var ids []uint
+	for c := range store.Iter() {
+		ids = append(ids, c.ID())
+	}
+	for _, id := range ids {
+		if err = store.Delete(id); err != nil {
+			return err
+		}
+	}
+	for _, c := range components {
+		comp, ok := c.(T)
+		if !ok {
+			return fmt.Errorf("expected component of type %v, got %T", reflect.TypeFor[T](), c)
+		}
+		if _, err = store.Add(r.clone(comp)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
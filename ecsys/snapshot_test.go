@@ -0,0 +1,155 @@
+package ecsys_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/shape"
+	"github.com/dwethmar/vork/component/sprite"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/dwethmar/vork/resource"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestECS_Snapshot(t *testing.T) {
+	setup := func(t *testing.T, ecs *ecsys.ECS) {
+		t.Helper()
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(parent, point.New(2, 2))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *sprite.New(child, "tag", sprite.SkeletonMoveDown1)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *shape.NewRectangle(child, 1, 2, color.NRGBA{A: 0xff})); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *hitbox.New(child, "main", 1, 1, point.Zero())); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	t.Run("should restore the state into the same ECS", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		setup(t, ecs)
+		wantPositions := ecsys.All[position.Position](ecs)
+		wantSprites := ecsys.All[sprite.Sprite](ecs)
+		snap := ecs.Snapshot()
+		if l := snap.Len(sprite.Type); l != 1 {
+			t.Errorf("Len(sprite) = %d, want 1", l)
+		}
+
+		// Change the world after the snapshot.
+		parent := wantPositions[0].Entity()
+		child := wantPositions[1].Entity()
+		if err := ecs.DeleteEntity(parent); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		extra, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+
+		if err = ecs.Restore(snap); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if diff := cmp.Diff(wantPositions, ecsys.All[position.Position](ecs)); diff != "" {
+			t.Errorf("All() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(wantSprites, ecsys.All[sprite.Sprite](ecs)); diff != "" {
+			t.Errorf("All() mismatch (-want +got):\n%s", diff)
+		}
+		if l := len(ecsys.All[hitbox.Hitbox](ecs)); l != 1 {
+			t.Errorf("All() len = %d, want 1", l)
+		}
+		if !ecs.Alive(parent) || !ecs.Alive(child) {
+			t.Error("restored entities should be alive")
+		}
+		if ecs.Alive(extra) {
+			t.Error("entity created after the snapshot should not be alive")
+		}
+		if p, err := ecs.Parent(child); err != nil || p != parent {
+			t.Errorf("Parent() = %v, %v, want %v", p, err, parent)
+		}
+
+		// The snapshot is not changed by changes to the restored world.
+		if err = ecs.DeleteEntity(child); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if err = ecs.Restore(snap); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if l := len(ecsys.All[sprite.Sprite](ecs)); l != 1 {
+			t.Errorf("All() len = %d, want 1", l)
+		}
+	})
+
+	t.Run("should restore into a fresh ECS with other stores", func(t *testing.T) {
+		src := ecsys.New(event.NewBus(), ecsys.NewStores())
+		setup(t, src)
+		dst := ecsys.New(event.NewBus(), ecsys.NewStores(ecsys.WithSparseSet[position.Position]()))
+		if err := dst.Restore(src.Snapshot()); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if diff := cmp.Diff(ecsys.All[position.Position](src), ecsys.All[position.Position](dst)); diff != "" {
+			t.Errorf("All() mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(ecsys.All[shape.Rectangle](src), ecsys.All[shape.Rectangle](dst)); diff != "" {
+			t.Errorf("All() mismatch (-want +got):\n%s", diff)
+		}
+
+		// New entities and components do not collide with the restored ones.
+		e, err := dst.CreateEntity(dst.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		for _, p := range ecsys.All[position.Position](src) {
+			if p.Entity() == e {
+				t.Errorf("CreateEntity() = %v, reuses a restored entity", e)
+			}
+		}
+		if _, err = ecsys.Add(dst, *sprite.New(e, "new", sprite.SkeletonMoveDown1)); err != nil {
+			t.Errorf("Add() error = %v", err)
+		}
+	})
+
+	t.Run("should restore persistent resources only", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		camera := resource.Camera{X: 1, Y: 2, Zoom: 1}
+		if err := ecs.InsertResource(camera, ecsys.Persistent()); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+		if err := ecs.InsertResource(resource.InputState{CursorX: 1}); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+		snap := ecs.Snapshot()
+
+		if err := ecs.InsertResource(resource.Camera{X: 10, Zoom: 2}); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+		input := resource.InputState{CursorX: 5}
+		if err := ecs.InsertResource(input); err != nil {
+			t.Fatalf("InsertResource() error = %v", err)
+		}
+		if err := ecs.Restore(snap); err != nil {
+			t.Fatalf("Restore() error = %v", err)
+		}
+		if got, err := ecsys.GetResource[resource.Camera](ecs); err != nil || got != camera {
+			t.Errorf("GetResource[Camera]() = %v, %v, want %v", got, err, camera)
+		}
+		if got, err := ecsys.GetResource[resource.InputState](ecs); err != nil || got != input {
+			t.Errorf("GetResource[InputState]() = %v, %v, want %v", got, err, input)
+		}
+		if l := len(ecs.PersistentResources()); l != 1 {
+			t.Errorf("PersistentResources() len = %d, want 1", l)
+		}
+	})
+}
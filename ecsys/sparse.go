@@ -0,0 +1,264 @@
+package ecsys
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/entity"
+)
+
+// SparseSetStore holds components in a sparse set and provides CRUD operations.
+// Components are kept by value in a dense slice and a sparse index maps component IDs
+// to their position in the dense slice. Add, Get and Delete are O(1) and iterating
+// the dense slice is cache friendly. The order of List is not stable after a delete.
+// Components returned by the store are copies.
+type SparseSetStore[C any, T ComponentPointer[C]] struct {
+	mu              sync.RWMutex
+	dense           []C
+	sparse          []int                    // Maps component ID to index in dense + 1, 0 means absent.
+	entityIndex     map[entity.Entity][]uint // Maps Entity ID to component IDs
+	nextID          uint
+	uniquePerEntity bool // Flag to enforce uniqueness per entity
+}
+
+// NewSparseSetStore creates a new sparse set store for a specific component type.
+// If uniquePerEntity is true, the store will enforce that only one component
+// per entity can be added.
+func NewSparseSetStore[C any, T ComponentPointer[C]](uniquePerEntity bool) *SparseSetStore[C, T] {
+	return &SparseSetStore[C, T]{
+		dense:           []C{},
+		sparse:          []int{},
+		entityIndex:     make(map[entity.Entity][]uint),
+		nextID:          1,
+		uniquePerEntity: uniquePerEntity,
+	}
+}
+
+// index returns the index of the component with the given ID in the dense slice.
+func (s *SparseSetStore[C, T]) index(id uint) (int, bool) {
+	if id >= uint(len(s.sparse)) || s.sparse[id] == 0 {
+		return 0, false
+	}
+	return s.sparse[id] - 1, true
+}
+
+// setIndex stores the index of the component with the given ID in the sparse index.
+func (s *SparseSetStore[C, T]) setIndex(id uint, i int) {
+	if id >= uint(len(s.sparse)) {
+		s.sparse = append(s.sparse, make([]int, int(id)+1-len(s.sparse))...)
+	}
+	s.sparse[id] = i + 1
+}
+
+// applyUniqueConstraint checks if a component violates the uniqueness constraint.
+func (s *SparseSetStore[C, T]) applyUniqueConstraint(e entity.Entity, id uint) error {
+	if !s.uniquePerEntity {
+		return nil
+	}
+	ids := s.entityIndex[e]
+	if len(ids) == 0 || (len(ids) == 1 && ids[0] == id) {
+		return nil
+	}
+	return ErrUniqueComponentViolation
+}
+
+// Add inserts a new component into the store.
+// If the component ID is zero, it assigns a new unique ID.
+// Enforces uniqueness per entity if the flag is set.
+func (s *SparseSetStore[C, T]) Add(c T) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := c.Entity()
+	if err := s.applyUniqueConstraint(e, c.ID()); err != nil {
+		return 0, err
+	}
+
+	if c.ID() == 0 {
+		c.SetID(s.nextID)
+	} else if _, ok := s.index(c.ID()); ok {
+		return 0, fmt.Errorf("component with ID %d already exists", c.ID())
+	}
+	id := c.ID()
+	if id >= s.nextID {
+		s.nextID = id + 1
+	}
+
+	s.dense = append(s.dense, *c)
+	s.setIndex(id, len(s.dense)-1)
+	s.entityIndex[e] = append(s.entityIndex[e], id)
+
+	return id, nil
+}
+
+// Get retrieves a component by its ID.
+func (s *SparseSetStore[C, T]) Get(id uint) (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, ok := s.index(id)
+	if !ok {
+		return nil, ErrComponentNotFound
+	}
+	c := s.dense[i]
+	return &c, nil
+}
+
+// First retrieves the first component associated with an entity.
+func (s *SparseSetStore[C, T]) First(e entity.Entity) (T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.entityIndex[e]
+	if len(ids) == 0 {
+		return nil, ErrEntityNotFound
+	}
+	i, _ := s.index(ids[0])
+	c := s.dense[i]
+	return &c, nil
+}
+
+// Update modifies an existing component in the store.
+func (s *SparseSetStore[C, T]) Update(c T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.applyUniqueConstraint(c.Entity(), c.ID()); err != nil {
+		return err
+	}
+
+	i, ok := s.index(c.ID())
+	if !ok {
+		return ErrComponentNotFound
+	}
+
+	// Move the component to the index of its new entity.
+	if old := T(&s.dense[i]).Entity(); old != c.Entity() {
+		s.removeFromEntityIndex(old, c.ID())
+		s.entityIndex[c.Entity()] = append(s.entityIndex[c.Entity()], c.ID())
+	}
+
+	s.dense[i] = *c
+	return nil
+}
+
+// List returns all components in the store.
+func (s *SparseSetStore[C, T]) List() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dense := slices.Clone(s.dense)
+	components := make([]T, len(dense))
+	for i := range dense {
+		components[i] = &dense[i]
+	}
+	return components
+}
+
+// Iter returns an iterator over all components in the store.
+// The store is read-locked while iterating. Unlike the other methods, the iterator
+// yields pointers into the store itself, they must not be modified or kept.
+func (s *SparseSetStore[C, T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for i := range s.dense {
+			if !yield(&s.dense[i]) {
+				return
+			}
+		}
+	}
+}
+
+// IterByEntity returns an iterator over the components associated with an entity.
+// The store is read-locked while iterating. The iterator yields pointers into the store itself.
+func (s *SparseSetStore[C, T]) IterByEntity(e entity.Entity) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for _, id := range s.entityIndex[e] {
+			i, _ := s.index(id)
+			if !yield(&s.dense[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ListByEntity retrieves all components associated with an entity.
+func (s *SparseSetStore[C, T]) ListByEntity(e entity.Entity) []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.entityIndex[e]
+	if len(ids) == 0 {
+		return nil
+	}
+
+	comps := make([]C, len(ids))
+	components := make([]T, len(ids))
+	for i, id := range ids {
+		j, _ := s.index(id)
+		comps[i] = s.dense[j]
+		components[i] = &comps[i]
+	}
+	return components
+}
+
+// Delete removes a component by its ID.
+func (s *SparseSetStore[C, T]) Delete(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, ok := s.index(id)
+	if !ok {
+		return ErrComponentNotFound
+	}
+	s.removeFromEntityIndex(T(&s.dense[i]).Entity(), id)
+	s.remove(i)
+	return nil
+}
+
+// DeleteByEntity removes all components associated with an entity.
+func (s *SparseSetStore[C, T]) DeleteByEntity(e entity.Entity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, exists := s.entityIndex[e]
+	if !exists {
+		return ErrEntityNotFound // No components to delete for this entity
+	}
+	for _, id := range ids {
+		if i, ok := s.index(id); ok {
+			s.remove(i)
+		}
+	}
+	delete(s.entityIndex, e)
+	return nil
+}
+
+// remove removes the component at index i from the dense slice by swapping it with the last component.
+func (s *SparseSetStore[C, T]) remove(i int) {
+	id := T(&s.dense[i]).ID()
+	last := len(s.dense) - 1
+	if i != last {
+		s.dense[i] = s.dense[last]
+		s.setIndex(T(&s.dense[i]).ID(), i)
+	}
+	var zero C
+	s.dense[last] = zero
+	s.dense = s.dense[:last]
+	s.sparse[id] = 0
+}
+
+// removeFromEntityIndex removes the component ID from the index of the entity.
+func (s *SparseSetStore[C, T]) removeFromEntityIndex(e entity.Entity, id uint) {
+	ids := slices.DeleteFunc(s.entityIndex[e], func(i uint) bool { return i == id })
+	if len(ids) == 0 {
+		delete(s.entityIndex, e)
+		return
+	}
+	s.entityIndex[e] = ids
+}
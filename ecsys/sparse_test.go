@@ -0,0 +1,46 @@
+package ecsys_test
+
+import (
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+)
+
+func TestNewStores_WithSparseSet(t *testing.T) {
+	t.Run("should use a sparse set store for the selected type", func(t *testing.T) {
+		stores := ecsys.NewStores(ecsys.WithSparseSet[position.Position]())
+		store, err := ecsys.StoreFor[*position.Position](stores)
+		if err != nil {
+			t.Fatalf("StoreFor() error = %v", err)
+		}
+		if _, ok := store.(*ecsys.SparseSetStore[position.Position, *position.Position]); !ok {
+			t.Fatalf("StoreFor() = %T, want *SparseSetStore", store)
+		}
+
+		ecs := ecsys.New(event.NewBus(), stores)
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(1, 2))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(parent, point.New(3, 4))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		p, err := ecs.GetAbsolutePosition(child)
+		if err != nil {
+			t.Fatalf("GetAbsolutePosition() error = %v", err)
+		}
+		if p != point.New(4, 6) {
+			t.Errorf("GetAbsolutePosition() = %v, want (4, 6)", p)
+		}
+		if err = ecs.DeleteEntity(parent); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if l := len(ecsys.All[position.Position](ecs)); l != 0 {
+			t.Errorf("All() len = %d, want 0", l)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+)
+
+// ErrStagingInProgress is returned when a transaction is started while another one is in progress on the handle.
+var ErrStagingInProgress = errors.New("a transaction is already in progress")
+
+// staging records the changes made through the handle of a transaction so they can be committed or rolled
+// back as a whole. The changes themselves are applied right away, an undo function is recorded for every
+// change and the events and observer calls are held back until commit.
+// Changes made through the ECS itself or the handles of other transactions are not recorded.
+type staging struct {
+	mu      sync.Mutex
+	pending []func() error   // held back event publications and observer calls, in order
+	undo    []func() error   // undo functions, in the order the changes were made
+	deleted []entity.Entity  // entities deleted in the transaction, their indices are freed on commit
+	owned   []ownedComponent // components written in the transaction, released when it ends
+}
+
+// ownedComponent is a component owned by a transaction, see owners.
+type ownedComponent struct {
+	owners *typeOwners
+	id     uint
+}
+
+// own records that the transaction owns the component with the given ID.
+func (st *staging) own(o *typeOwners, id uint) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.owned = append(st.owned, ownedComponent{owners: o, id: id})
+}
+
+// release gives up the ownership of the components written in the transaction.
+func (st *staging) release() {
+	st.mu.Lock()
+	owned := st.owned
+	st.owned = nil
+	st.mu.Unlock()
+	for _, c := range owned {
+		c.owners.release(st, c.id)
+	}
+}
+
+// begin starts a transaction and returns the handle that records its changes.
+func (s *ECS) begin() (*ECS, error) {
+	if s.isStaging() {
+		return nil, ErrStagingInProgress
+	}
+	return &ECS{core: s.core, staging: &staging{}}, nil
+}
+
+// commit frees the indices of the deleted entities, then publishes the held back events and calls the held
+// back observers in the order the changes were made. Handlers that change the ECS meanwhile are not part
+// of the transaction.
+func (s *ECS) commit() error {
+	st := s.staging
+	st.mu.Lock()
+	pending, deleted := st.pending, st.deleted
+	st.pending, st.undo, st.deleted = nil, nil, nil
+	st.mu.Unlock()
+
+	s.mu.Lock()
+	for _, e := range deleted {
+		s.entities.freeIndex(e)
+	}
+	s.mu.Unlock()
+	st.release()
+	for _, dispatch := range pending {
+		if err := dispatch(); err != nil {
+			return fmt.Errorf("could not dispatch change: %w", err)
+		}
+	}
+	return nil
+}
+
+// rollback undoes the recorded changes in reverse order and drops the held back events and observer calls.
+func (s *ECS) rollback() error {
+	st := s.staging
+	st.mu.Lock()
+	undo := st.undo
+	st.pending, st.undo, st.deleted = nil, nil, nil
+	st.mu.Unlock()
+
+	var errs []error
+	for _, u := range slices.Backward(undo) {
+		errs = append(errs, u())
+	}
+	st.release()
+	s.resetCaches()
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("could not roll back: %w", err)
+	}
+	return nil
+}
+
+// publish publishes the event, or holds it back until commit in a transaction.
+func (s *ECS) publish(e event.Event) error {
+	return s.dispatch(func() error { return s.eventBus.Publish(e) })
+}
+
+// dispatch calls fn, or holds the call back until commit in a transaction.
+func (s *ECS) dispatch(fn func() error) error {
+	st := s.staging
+	if st == nil {
+		return fn()
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.pending = append(st.pending, fn)
+	return nil
+}
+
+// recordUndo records a function that undoes a change in a transaction.
+func (s *ECS) recordUndo(undo func() error) {
+	st := s.staging
+	if st == nil || undo == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.undo = append(st.undo, undo)
+}
+
+// recordComponentUndo records a function that undoes a change to the component with the given ID in a
+// transaction. It is only called on rollback if the transaction still owns the component, see owners.
+func (s *ECS) recordComponentUndo(t component.Type, id uint, undo func() error) {
+	if s.staging == nil {
+		return
+	}
+	s.recordUndo(s.owners.of(t).guard(s.staging, id, undo))
+}
+
+// destroy deletes the entity from the entity table. In a transaction the index of the entity is only
+// freed on commit, so it is not reused by another entity before the deletion is committed.
+// It must not be called with the lock held.
+func (s *ECS) destroy(e entity.Entity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.staging
+	if st == nil {
+		s.entities.destroy(e)
+		return
+	}
+	if !s.entities.retire(e) {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.deleted = append(st.deleted, e)
+	st.undo = append(st.undo, func() error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.entities.revive(e)
+		return nil
+	})
+}
+
+// isStaging reports whether the handle records the changes of a transaction.
+func (s *ECS) isStaging() bool {
+	return s.staging != nil
+}
@@ -0,0 +1,102 @@
+package ecsys
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/dwethmar/vork/component"
+)
+
+// Stats holds statistics about the world at the moment they were taken.
+type Stats struct {
+	Entities       int            `json:"entities"`        // Alive entities, not counting the root.
+	Stores         []StoreStats   `json:"stores"`          // Stores in registration order.
+	Events         map[string]int `json:"events"`          // Published events per event type.
+	HierarchyDepth int            `json:"hierarchy_depth"` // Levels below the root.
+	Orphans        int            `json:"orphans"`         // Components of entities that are not alive.
+}
+
+// StoreStats holds statistics about the store of a component type.
+type StoreStats struct {
+	Type       component.Type `json:"type"`
+	Components int            `json:"components"`
+	Bytes      int            `json:"bytes"`   // Approximate memory used by the components.
+	Orphans    int            `json:"orphans"` // Components of entities that are not alive.
+}
+
+// Stats returns statistics about the entities, components, events and hierarchy of the world.
+// The memory of a store is approximated from the size of its components and the strings,
+// slices and maps they hold, the overhead of the store itself is not counted.
+func (s *ECS) Stats() Stats {
+	s.mu.RLock()
+	alive := s.entities.clone()
+	s.mu.RUnlock()
+
+	st := Stats{
+		Entities:       len(alive.list()) - 1,
+		Events:         s.eventBus.PublishCounts(),
+		HierarchyDepth: s.hierarchy.Depth(),
+	}
+	for _, r := range s.stores.types {
+		ss := StoreStats{Type: r.componentType()}
+		for _, c := range r.list(s.stores) {
+			ss.Components++
+			ss.Bytes += sizeOf(reflect.ValueOf(c))
+			if !alive.isAlive(c.Entity()) {
+				ss.Orphans++
+			}
+		}
+		st.Orphans += ss.Orphans
+		st.Stores = append(st.Stores, ss)
+	}
+	return st
+}
+
+// Store returns the statistics of the store of the component type, or false if it is not registered.
+func (st Stats) Store(t component.Type) (StoreStats, bool) {
+	for _, ss := range st.Stores {
+		if ss.Type == t {
+			return ss, true
+		}
+	}
+	return StoreStats{}, false
+}
+
+// JSON returns the statistics as indented JSON.
+func (st Stats) JSON() ([]byte, error) {
+	return json.MarshalIndent(st, "", "  ")
+}
+
+// sizeOf returns the approximate number of bytes used by the value and the values it refers to.
+func sizeOf(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return int(v.Type().Size())
+		}
+		return int(v.Type().Size()) + sizeOf(v.Elem())
+	case reflect.String:
+		return int(v.Type().Size()) + v.Len()
+	case reflect.Slice:
+		size := int(v.Type().Size()) + (v.Cap()-v.Len())*int(v.Type().Elem().Size())
+		for i := range v.Len() {
+			size += sizeOf(v.Index(i))
+		}
+		return size
+	case reflect.Map:
+		size := int(v.Type().Size())
+		for it := v.MapRange(); it.Next(); {
+			size += sizeOf(it.Key()) + sizeOf(it.Value())
+		}
+		return size
+	case reflect.Struct:
+		size := int(v.Type().Size())
+		for i := range v.NumField() {
+			// The fields are part of the size of the struct, only add what they refer to.
+			size += sizeOf(v.Field(i)) - int(v.Field(i).Type().Size())
+		}
+		return size
+	default:
+		return int(v.Type().Size())
+	}
+}
@@ -0,0 +1,90 @@
+package ecsys_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/tags"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestECS_Stats(t *testing.T) {
+	t.Run("should report the entities, components, events and hierarchy", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecs.CreateEntity(parent, point.Zero()); err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *tags.New(parent, "a", "b")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		st := ecs.Stats()
+		if st.Entities != 2 {
+			t.Errorf("Entities = %d, want 2", st.Entities)
+		}
+		if st.HierarchyDepth != 2 {
+			t.Errorf("HierarchyDepth = %d, want 2", st.HierarchyDepth)
+		}
+		if diff := cmp.Diff(map[string]int{entity.CreatedEventType: 2, position.CreatedEventType: 2, tags.CreatedEventType: 1}, st.Events); diff != "" {
+			t.Errorf("Events mismatch (-want +got):\n%s", diff)
+		}
+		pos, ok := st.Store(position.Type)
+		if !ok || pos.Components != 2 || pos.Bytes == 0 {
+			t.Errorf("Store(position) = %+v, want 2 components", pos)
+		}
+		tg, ok := st.Store(tags.Type)
+		if !ok || tg.Components != 1 || tg.Bytes <= pos.Bytes/2 {
+			t.Errorf("Store(tags) = %+v, want 1 component larger than a position", tg)
+		}
+		if st.Orphans != 0 {
+			t.Errorf("Orphans = %d, want 0", st.Orphans)
+		}
+	})
+
+	t.Run("should count the components of entities that are not alive", func(t *testing.T) {
+		stores := ecsys.NewStores()
+		ecs := ecsys.New(event.NewBus(), stores)
+		e := newEntities(t, ecs, 1)[0]
+		// Components that are added directly to the store bypass the entity table.
+		store, err := ecsys.StoreFor[*tags.Tags](stores)
+		if err != nil {
+			t.Fatalf("StoreFor() error = %v", err)
+		}
+		if err = ecs.DeleteEntity(e); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+		if _, err = store.Add(tags.New(e, "ghost")); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		st := ecs.Stats()
+		if tg, _ := st.Store(tags.Type); st.Orphans != 1 || tg.Orphans != 1 {
+			t.Errorf("Orphans = %d, Store(tags).Orphans = %d, want 1", st.Orphans, tg.Orphans)
+		}
+	})
+
+	t.Run("should encode as JSON", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		newEntities(t, ecs, 3)
+		b, err := ecs.Stats().JSON()
+		if err != nil {
+			t.Fatalf("JSON() error = %v", err)
+		}
+		var got ecsys.Stats
+		if err = json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if diff := cmp.Diff(ecs.Stats(), got); diff != "" {
+			t.Errorf("Stats mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -2,15 +2,10 @@ package ecsys
 
 import (
 	"errors"
+	"fmt"
+	"iter"
 
 	"github.com/dwethmar/vork/component"
-	"github.com/dwethmar/vork/component/controllable"
-	"github.com/dwethmar/vork/component/hitbox"
-	"github.com/dwethmar/vork/component/position"
-	"github.com/dwethmar/vork/component/shape"
-	"github.com/dwethmar/vork/component/skeleton"
-	"github.com/dwethmar/vork/component/sprite"
-	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/entity"
 )
 
@@ -28,79 +23,92 @@ var (
 type Store[T component.Component] interface {
 	Add(T) (uint, error)                // Add a new component to the store.
 	Get(uint) (T, error)                // Get a component by its ID.
+	First(entity.Entity) (T, error)     // Get the first component associated with an entity.
 	Update(T) error                     // Update an existing component.
 	List() []T                          // List all components in the store.
+	ListByEntity(entity.Entity) []T     // List all components associated with an entity.
 	Delete(uint) error                  // Delete a component by its ID.
 	DeleteByEntity(entity.Entity) error // Delete all components associated with an entity.
+	// Iter and IterByEntity iterate over all components, or the components of an entity,
+	// without copying them to a slice. The store is read-locked while iterating, so the
+	// loop must not change the store. The components must not be kept after the loop.
+	Iter() iter.Seq[T]
+	IterByEntity(entity.Entity) iter.Seq[T]
 }
 
-// ControllableStore manages Controllable components
-// Includes an additional method to get the first Controllable by an entity.
-type ControllableStore interface {
-	Store[*controllable.Controllable]
-	First(entity.Entity) (*controllable.Controllable, error)
+// Stores is a collection of component stores used in the ECS.
+// It holds one store for every component type that was registered when it was created.
+type Stores struct {
+	types  []registered
+	stores map[component.Type]any
 }
 
-// PositionStore manages Position components
-// Includes an additional method to get the first Position by an entity.
-type PositionStore interface {
-	Store[*position.Position]
-	First(entity.Entity) (*position.Position, error)
-}
+// StoreOption configures the stores created by NewStores.
+type StoreOption func(*storesConfig)
 
-// VelocityStore manages Velocity components.
-type VelocityStore interface {
-	Store[*velocity.Velocity]
-	First(entity.Entity) (*velocity.Velocity, error)
+// storesConfig holds the store factories that override the registered ones.
+type storesConfig struct {
+	factories map[component.Type]func(uniquePerEntity bool) any
 }
 
-// HitboxStore manages Velocity components.
-type HitboxStore interface {
-	Store[*hitbox.Hitbox]
-	ListByEntity(entity.Entity) []*hitbox.Hitbox
-}
-
-// RectanglesStore manages Rectangle components (for shapes)
-// Includes an additional method to get the first Rectangle by an entity.
-type RectanglesStore interface {
-	Store[*shape.Rectangle]
-	ListByEntity(entity.Entity) []*shape.Rectangle
+// WithStore makes NewStores use the given factory to create the store of component type T.
+func WithStore[T component.Component](newStore func(uniquePerEntity bool) Store[T]) StoreOption {
+	return func(c *storesConfig) {
+		if r, err := lookup[T](); err == nil {
+			c.factories[r.Type] = func(uniquePerEntity bool) any { return newStore(uniquePerEntity) }
+		}
+	}
 }
 
-// SpriteStore manages Sprite components
-// Includes an additional method to list all sprites associated with an entity.
-type SpriteStore interface {
-	Store[*sprite.Sprite]
-	ListByEntity(entity.Entity) []*sprite.Sprite
+// WithSparseSet makes NewStores use a SparseSetStore for component type C.
+func WithSparseSet[C any, T ComponentPointer[C]]() StoreOption {
+	return WithStore(func(uniquePerEntity bool) Store[T] {
+		return NewSparseSetStore[C, T](uniquePerEntity)
+	})
 }
 
-// SkeletonStore manages Skeleton components
-// Includes an additional method to get the first Skeleton by an entity.
-type SkeletonStore interface {
-	Store[*skeleton.Skeleton]
-	First(entity.Entity) (*skeleton.Skeleton, error)
+// NewStores creates a new set of component stores, one for each registered component type.
+// By default the store of the registration is used, which can be overridden per type with options.
+func NewStores(opts ...StoreOption) *Stores {
+	cfg := &storesConfig{
+		factories: make(map[component.Type]func(uniquePerEntity bool) any),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	s := &Stores{
+		types:  registrations(),
+		stores: make(map[component.Type]any),
+	}
+	for _, r := range s.types {
+		if f, ok := cfg.factories[r.componentType()]; ok {
+			s.stores[r.componentType()] = f(r.uniquePerEntity())
+		} else {
+			s.stores[r.componentType()] = r.newStore()
+		}
+	}
+	return s
 }
 
-// Stores is a collection of component stores used in the ECS.
-type Stores struct {
-	Controllable ControllableStore
-	Position     PositionStore
-	Velocity     VelocityStore
-	Hitbox       HitboxStore
-	Rectangle    RectanglesStore
-	Sprite       SpriteStore
-	Skeleton     SkeletonStore
+// StoreFor returns the store of component type T.
+func StoreFor[T component.Component](s *Stores) (Store[T], error) {
+	r, err := lookup[T]()
+	if err != nil {
+		return nil, err
+	}
+	store, ok := s.stores[r.Type].(Store[T])
+	if !ok {
+		return nil, fmt.Errorf("no store for component type %q: %w", r.Type, ErrComponentNotRegistered)
+	}
+	return store, nil
 }
 
-// NewStores creates a new set of component stores.
-func NewStores() *Stores {
-	return &Stores{
-		Controllable: NewMemStore[*controllable.Controllable](true),
-		Position:     NewMemStore[*position.Position](true),
-		Velocity:     NewMemStore[*velocity.Velocity](true),
-		Hitbox:       NewMemStore[*hitbox.Hitbox](false),
-		Rectangle:    NewMemStore[*shape.Rectangle](true),
-		Sprite:       NewMemStore[*sprite.Sprite](false),
-		Skeleton:     NewMemStore[*skeleton.Skeleton](true),
+// hasComponent reports whether the entity has a component of the given type.
+func (s *Stores) hasComponent(t component.Type, e entity.Entity) bool {
+	for _, r := range s.types {
+		if r.componentType() == t {
+			return r.hasEntity(s, e)
+		}
 	}
+	return false
 }
@@ -0,0 +1,34 @@
+package ecsys
+
+import (
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/event"
+)
+
+// ComponentChange is a change to a component of type C, see SubscribeComponent.
+type ComponentChange[C any] struct {
+	Event     string // Type of the event, such as "velocity.created".
+	Component C      // The component as it was when the event was published.
+	Deleted   bool   // The component was deleted.
+}
+
+// SubscribeComponent adds a handler to the bus for the created, updated and deleted events
+// of components of type C. Components of types that publish no events are observed with
+// OnAdd, OnChange and OnRemove instead.
+// It returns an identifier for the handler, which can be used to unsubscribe it later.
+func SubscribeComponent[C any, T ComponentPointer[C]](bus *event.Bus, handler func(ComponentChange[C]) error, opts ...event.SubscribeOption) int {
+	// The component type indexes the subscription, the Go type tells components of the same type apart.
+	match := event.And(component.MatchType(T(new(C)).Type()), event.MatcherFunc(func(e event.Event) bool {
+		ce, ok := e.(component.Event)
+		if !ok {
+			return false
+		}
+		_, ok = ce.Component().(T)
+		return ok
+	}))
+	return bus.Subscribe(match, func(e event.Event) error {
+		ce := e.(component.Event) //nolint: forcetypeassert // matched above
+		c := ce.Component().(T)   //nolint: forcetypeassert // matched above
+		return handler(ComponentChange[C]{Event: ce.Event(), Component: *c, Deleted: ce.Deleted()})
+	}, opts...)
+}
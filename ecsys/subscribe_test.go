@@ -0,0 +1,201 @@
+package ecsys_test
+
+import (
+	"testing"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/event/mouse"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+// RenamedComponent is a component type whose events are not named after the type.
+type RenamedComponent struct {
+	I uint
+	E entity.Entity
+}
+
+func (r *RenamedComponent) ID() uint              { return r.I }
+func (r *RenamedComponent) SetID(i uint)          { r.I = i }
+func (r *RenamedComponent) Type() component.Type  { return "renamed" }
+func (r *RenamedComponent) Entity() entity.Entity { return r.E }
+
+// renamedEvent is an event of a RenamedComponent.
+type renamedEvent struct {
+	name    string
+	c       RenamedComponent
+	deleted bool
+}
+
+func (e *renamedEvent) Event() string                  { return e.name }
+func (e *renamedEvent) ComponentID() uint              { return e.c.I }
+func (e *renamedEvent) ComponentType() component.Type  { return e.c.Type() }
+func (e *renamedEvent) Component() component.Component { return &e.c }
+func (e *renamedEvent) Deleted() bool                  { return e.deleted }
+
+func init() {
+	ecsys.Register(ecsys.Registration[*RenamedComponent]{
+		Type:         "renamed",
+		CreatedEvent: func(r *RenamedComponent) event.Event { return &renamedEvent{name: "renamed.added", c: *r} },
+		DeletedEvent: func(r *RenamedComponent) event.Event {
+			return &renamedEvent{name: "renamed.removed", c: *r, deleted: true}
+		},
+	})
+}
+
+func TestSubscribeComponent(t *testing.T) {
+	t.Run("should pass the changes of the component type", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		var got []ecsys.ComponentChange[velocity.Velocity]
+		ecsys.SubscribeComponent(bus, func(c ecsys.ComponentChange[velocity.Velocity]) error {
+			got = append(got, c)
+			return nil
+		})
+
+		// Creating the entity publishes a position event, which is not passed.
+		e := newEntities(t, ecs, 1)[0]
+		v := velocity.New(e, point.New(1, 2))
+		id, err := ecsys.Add(ecs, *v)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		v.I = id
+		if err = ecs.DeleteEntity(e); err != nil {
+			t.Fatalf("DeleteEntity() error = %v", err)
+		}
+
+		want := []ecsys.ComponentChange[velocity.Velocity]{
+			{Event: velocity.CreatedEventType, Component: *v},
+			{Event: velocity.DeletedEventType, Component: *v, Deleted: true},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("changes mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should match on the component, not on the event name", func(t *testing.T) {
+		bus := event.NewBus()
+		handled := 0
+		ecsys.SubscribeComponent(bus, func(ecsys.ComponentChange[position.Position]) error {
+			handled++
+			return nil
+		})
+		if err := bus.Publish(velocity.NewCreatedEvent(*velocity.New(1, point.Zero()))); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if err := bus.Publish(position.NewUpdatedEvent(*position.New(0, 1, point.Zero()))); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if handled != 1 {
+			t.Errorf("handled %d events, want 1", handled)
+		}
+	})
+
+	t.Run("should pass the changes of a component type with other event names", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		var got []string
+		ecsys.SubscribeComponent(bus, func(c ecsys.ComponentChange[RenamedComponent]) error {
+			got = append(got, c.Event)
+			return nil
+		})
+		r := RenamedComponent{E: 1}
+		var err error
+		if r.I, err = ecsys.Add(ecs, r); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err = ecsys.Delete(ecs, r); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if diff := cmp.Diff([]string{"renamed.added", "renamed.removed"}, got); diff != "" {
+			t.Errorf("changes mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should index the subscription by the component events", func(t *testing.T) {
+		bus := event.NewBus()
+		ecsys.SubscribeComponent(bus, func(ecsys.ComponentChange[velocity.Velocity]) error { return nil })
+		subs := bus.Subscriptions()
+		if len(subs) != 1 {
+			t.Fatalf("expected 1 subscription, got %d", len(subs))
+		}
+		ix, ok := subs[0].Matcher().(event.Indexer)
+		if !ok {
+			t.Fatalf("expected the matcher to be an Indexer")
+		}
+		types, ok := ix.EventTypes()
+		want := []string{velocity.CreatedEventType, velocity.UpdatedEventType, velocity.DeletedEventType}
+		if !ok {
+			t.Fatalf("EventTypes() ok = false, want true")
+		}
+		if diff := cmp.Diff(want, types); diff != "" {
+			t.Errorf("EventTypes() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+}
+
+func TestComponentMatchers(t *testing.T) {
+	v := velocity.New(1, point.Zero())
+	p := position.New(0, 2, point.Zero())
+	tests := []struct {
+		name    string
+		matcher event.Matcher
+		event   event.Event
+		want    bool
+	}{
+		{"MatchType matches the component type", component.MatchType(velocity.Type), velocity.NewDeletedEvent(*v), true},
+		{"MatchType does not match other types", component.MatchType(velocity.Type), position.NewCreatedEvent(*p), false},
+		{"MatchType does not match other events", component.MatchType(velocity.Type), mouse.NewLeftClickedEvent(0, 0), false},
+		{"MatchEntity matches the entity", component.MatchEntity(2), position.NewUpdatedEvent(*p), true},
+		{"MatchEntity does not match other entities", component.MatchEntity(2), velocity.NewCreatedEvent(*v), false},
+		{
+			"combined with event matchers",
+			event.And(component.MatchEntity(1), event.MatchGlob("*.deleted")),
+			velocity.NewDeletedEvent(*v),
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Match(tt.event); got != tt.want {
+				t.Errorf("Match(%s) = %v, want %v", tt.event.Event(), got, tt.want)
+			}
+		})
+	}
+
+	t.Run("MatchType is indexed by the component events", func(t *testing.T) {
+		types, ok := component.MatchType(velocity.Type, position.Type).(event.Indexer).EventTypes()
+		want := []string{
+			velocity.CreatedEventType, velocity.UpdatedEventType, velocity.DeletedEventType,
+			position.CreatedEventType, position.UpdatedEventType, position.DeletedEventType,
+		}
+		if !ok {
+			t.Fatalf("EventTypes() ok = false, want true")
+		}
+		if diff := cmp.Diff(want, types); diff != "" {
+			t.Errorf("EventTypes() mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("MatchType is indexed by the registered events", func(t *testing.T) {
+		types, ok := component.MatchType("renamed").(event.Indexer).EventTypes()
+		if !ok {
+			t.Fatalf("EventTypes() ok = false, want true")
+		}
+		if diff := cmp.Diff([]string{"renamed.added", "renamed.removed"}, types); diff != "" {
+			t.Errorf("EventTypes() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("MatchType of an unregistered type is not indexed", func(t *testing.T) {
+		if _, ok := component.MatchType(velocity.Type, "unregistered").(event.Indexer).EventTypes(); ok {
+			t.Errorf("EventTypes() ok = true, want false")
+		}
+	})
+}
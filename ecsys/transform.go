@@ -0,0 +1,83 @@
+package ecsys
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/transform"
+	"github.com/dwethmar/vork/entity"
+)
+
+// worldTransforms caches the world transforms of entities. The world transform of an entity
+// is invalidated when the position or transform of the entity or one of its ancestors changes.
+type worldTransforms struct {
+	mu    sync.Mutex
+	cache map[entity.Entity]transform.Matrix
+}
+
+func newWorldTransforms() *worldTransforms {
+	return &worldTransforms{cache: make(map[entity.Entity]transform.Matrix)}
+}
+
+// WorldTransform returns the transform from the local space of the entity to the world.
+// It is the local transform of the entity composed with the local transforms of its ancestors.
+func (s *ECS) WorldTransform(e entity.Entity) (transform.Matrix, error) {
+	s.worlds.mu.Lock()
+	defer s.worlds.mu.Unlock()
+	return s.worldTransform(e)
+}
+
+// worldTransform returns the world transform of the entity, computing and caching
+// the world transforms of its ancestors if needed. s.worlds.mu must be held.
+func (s *ECS) worldTransform(e entity.Entity) (transform.Matrix, error) {
+	if e == s.hierarchy.Root() {
+		return transform.Identity(), nil
+	}
+	if m, ok := s.worlds.cache[e]; ok {
+		return m, nil
+	}
+	parent, err := s.hierarchy.Parent(e)
+	if err != nil {
+		return transform.Matrix{}, err
+	}
+	pos, err := Get[position.Position](s, e)
+	if err != nil {
+		return transform.Matrix{}, err
+	}
+	var local transform.Matrix
+	if t, tErr := Get[transform.Transform](s, e); tErr == nil {
+		local = transform.Local(pos.Point, &t)
+	} else {
+		local = transform.Local(pos.Point, nil)
+	}
+	pm, err := s.worldTransform(parent)
+	if err != nil {
+		return transform.Matrix{}, fmt.Errorf("could not get world transform of parent %v: %w", parent, err)
+	}
+	m := pm.Mul(local)
+	s.worlds.cache[e] = m
+	return m, nil
+}
+
+// invalidate removes the world transforms of the entities from the cache.
+func (w *worldTransforms) invalidate(entities []entity.Entity) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, e := range entities {
+		delete(w.cache, e)
+	}
+}
+
+// reset empties the cache of world transforms.
+func (w *worldTransforms) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	clear(w.cache)
+}
+
+// invalidateEntityTransform is the hook of the transform component.
+func invalidateEntityTransform(s *ECS, t *transform.Transform) error {
+	s.invalidate(t.Entity())
+	return nil
+}
@@ -0,0 +1,231 @@
+package ecsys_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/transform"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var approx = cmpopts.EquateApprox(0, 1e-9)
+
+func TestMatrix(t *testing.T) {
+	t.Run("Local should scale, then rotate and then translate", func(t *testing.T) {
+		m := transform.Local(point.New(10, 20), transform.New(0, math.Pi/2, 2, 3))
+		x, y := m.Apply(1, 1)
+		if diff := cmp.Diff([]float64{7, 22}, []float64{x, y}, approx); diff != "" {
+			t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Local without transform should only translate", func(t *testing.T) {
+		want := transform.Matrix{A: 1, D: 1, Tx: 3, Ty: 4}
+		if diff := cmp.Diff(want, transform.Local(point.New(3, 4), nil)); diff != "" {
+			t.Errorf("Local() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Local with an empty transform should only translate", func(t *testing.T) {
+		want := transform.Matrix{A: 1, D: 1, Tx: 3, Ty: 4}
+		if diff := cmp.Diff(want, transform.Local(point.New(3, 4), transform.Empty())); diff != "" {
+			t.Errorf("Local() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Mul should apply the right matrix first", func(t *testing.T) {
+		parent := transform.Local(point.New(100, 0), transform.New(0, math.Pi/2, 1, 1))
+		child := transform.Local(point.New(10, 0), nil)
+		x, y := parent.Mul(child).Translation()
+		if diff := cmp.Diff([]float64{100, 10}, []float64{x, y}, approx); diff != "" {
+			t.Errorf("Translation() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Rotation and Scale should decompose the matrix", func(t *testing.T) {
+		m := transform.Local(point.Zero(), transform.New(0, 0.5, 2, -3))
+		sx, sy := m.Scale()
+		if diff := cmp.Diff([]float64{0.5, 2, -3}, []float64{m.Rotation(), sx, sy}, approx); diff != "" {
+			t.Errorf("Rotation() and Scale() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestECS_WorldTransform(t *testing.T) {
+	t.Run("should compose the transforms of the ancestors", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(100, 50))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *transform.New(parent, math.Pi/2, 2, 2)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(parent, point.New(10, 0))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+
+		m, err := ecs.WorldTransform(child)
+		if err != nil {
+			t.Fatalf("WorldTransform() error = %v", err)
+		}
+		x, y := m.Translation()
+		sx, sy := m.Scale()
+		if diff := cmp.Diff([]float64{100, 70, math.Pi / 2, 2, 2}, []float64{x, y, m.Rotation(), sx, sy}, approx); diff != "" {
+			t.Errorf("WorldTransform() mismatch (-want +got):\n%s", diff)
+		}
+		got, err := ecs.GetAbsolutePosition(child)
+		if err != nil {
+			t.Fatalf("GetAbsolutePosition() error = %v", err)
+		}
+		if diff := cmp.Diff(point.New(100, 70), got); diff != "" {
+			t.Errorf("GetAbsolutePosition() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should not collapse the subtree of an empty transform", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(100, 50))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		tr := transform.Empty()
+		tr.E = parent
+		if _, err = ecsys.Add(ecs, *tr); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(parent, point.New(10, 5))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		got, err := ecs.GetAbsolutePosition(child)
+		if err != nil {
+			t.Fatalf("GetAbsolutePosition() error = %v", err)
+		}
+		if diff := cmp.Diff(point.New(110, 55), got); diff != "" {
+			t.Errorf("GetAbsolutePosition() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should collapse the subtree of a zero scale", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(100, 50))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *transform.New(parent, 0, 0, 0)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(parent, point.New(10, 5))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		got, err := ecs.GetAbsolutePosition(child)
+		if err != nil {
+			t.Fatalf("GetAbsolutePosition() error = %v", err)
+		}
+		if diff := cmp.Diff(point.New(100, 50), got); diff != "" {
+			t.Errorf("GetAbsolutePosition() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should add up the positions without transforms", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e := ecs.Root()
+		for _, p := range []point.Point{point.New(1, 2), point.New(3, 4), point.New(-5, 6)} {
+			var err error
+			if e, err = ecs.CreateEntity(e, p); err != nil {
+				t.Fatalf("CreateEntity() error = %v", err)
+			}
+		}
+		got, err := ecs.GetAbsolutePosition(e)
+		if err != nil {
+			t.Fatalf("GetAbsolutePosition() error = %v", err)
+		}
+		if diff := cmp.Diff(point.New(-1, 12), got); diff != "" {
+			t.Errorf("GetAbsolutePosition() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should invalidate the descendants when an ancestor changes", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		parent, err := ecs.CreateEntity(ecs.Root(), point.New(10, 10))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		other, err := ecs.CreateEntity(ecs.Root(), point.New(-10, -10))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		child, err := ecs.CreateEntity(parent, point.New(1, 0))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		grandchild, err := ecs.CreateEntity(child, point.New(1, 0))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		assertPosition := func(want point.Point) {
+			t.Helper()
+			got, err := ecs.GetAbsolutePosition(grandchild)
+			if err != nil {
+				t.Fatalf("GetAbsolutePosition() error = %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("GetAbsolutePosition() mismatch (-want +got):\n%s", diff)
+			}
+		}
+		assertPosition(point.New(12, 10))
+
+		// Moving the parent moves the grandchild.
+		pos, err := ecsys.Get[position.Position](ecs, parent)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		pos.Point = point.New(20, 20)
+		if err = ecsys.Update(ecs, pos); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		assertPosition(point.New(22, 20))
+
+		// Adding and updating a transform on the parent rotates and scales the grandchild.
+		tr := transform.New(parent, math.Pi, 1, 1)
+		if tr.I, err = ecsys.Add(ecs, *tr); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		assertPosition(point.New(18, 20))
+		tr.Rotation, tr.ScaleX = 0, 3
+		if err = ecsys.Update(ecs, *tr); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		assertPosition(point.New(26, 20))
+
+		// Reparenting the child moves the grandchild along.
+		pos, err = ecsys.Get[position.Position](ecs, child)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		pos.Parent = other
+		if err = ecsys.Update(ecs, pos); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		assertPosition(point.New(-8, -10))
+	})
+
+	t.Run("should return an error if the entity has no position", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e, err := ecs.CreateEmptyEntity()
+		if err != nil {
+			t.Fatalf("CreateEmptyEntity() error = %v", err)
+		}
+		if _, err = ecs.WorldTransform(e); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
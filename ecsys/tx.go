@@ -0,0 +1,41 @@
+package ecsys
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tx calls fn and applies the changes it makes to the ECS as a whole.
+// The adds, updates and deletes fn makes across all stores and the hierarchy are applied right away
+// and an undo is recorded for each of them; only the events and observer calls are held back. They are
+// published in order once fn returns without error. If fn returns an error or panics, the changes are
+// undone in reverse order, including created and deleted entities, and no events are published.
+// The changes are not isolated: other goroutines see them before the transaction is committed.
+//
+// fn receives a handle to the ECS, only the changes made through the handle are part of the
+// transaction. Changes made meanwhile by other goroutines, for example systems that run in parallel,
+// are neither held back nor rolled back, and transactions can run next to each other. A rollback skips
+// the components and resources that were written by someone else after the transaction wrote them, so it
+// does not overwrite their changes. The indices of
+// entities deleted in the transaction are only reused after it has been committed.
+// The handle must not be used after fn returns. Transactions cannot be nested. Changes made by event
+// handlers while the events are published on commit are not part of the transaction.
+func (s *ECS) Tx(fn func(tx *ECS) error) (err error) {
+	tx, err := s.begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.rollback()
+			panic(r)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		if rerr := tx.rollback(); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+	return tx.commit()
+}
@@ -0,0 +1,293 @@
+package ecsys_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/skeleton"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/dwethmar/vork/resource"
+)
+
+func TestECS_Tx(t *testing.T) {
+	t.Run("should publish events on commit", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		published := 0
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(event.Event) error {
+			published++
+			return nil
+		})
+
+		var e entity.Entity
+		err := ecs.Tx(func(tx *ecsys.ECS) error {
+			var err error
+			if e, err = tx.CreateEntity(tx.Root(), point.New(1, 2)); err != nil {
+				return err
+			}
+			if _, err = ecsys.Add(tx, *skeleton.New(e)); err != nil {
+				return err
+			}
+			if published != 0 {
+				t.Errorf("no events should be published before commit, got %d", published)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Tx() error = %v", err)
+		}
+		if published != 3 {
+			t.Errorf("published = %d, want 3", published)
+		}
+		if _, err = ecsys.Get[skeleton.Skeleton](ecs, e); err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	})
+
+	t.Run("should roll back everything if fn returns an error", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		existing, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		published := 0
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(event.Event) error {
+			published++
+			return nil
+		})
+
+		wantErr := errors.New("failed")
+		var e entity.Entity
+		err = ecs.Tx(func(tx *ecsys.ECS) error {
+			if e, err = tx.CreateEntity(existing, point.New(1, 2)); err != nil {
+				return err
+			}
+			if _, err = ecsys.Add(tx, *skeleton.New(e)); err != nil {
+				return err
+			}
+			if _, err = ecsys.Add(tx, *velocity.New(e, point.Zero())); err != nil {
+				return err
+			}
+			if err = tx.DeleteEntity(existing); err != nil {
+				return err
+			}
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Tx() error = %v, want %v", err, wantErr)
+		}
+		if published != 0 {
+			t.Errorf("no events should be published, got %d", published)
+		}
+		if ecs.Alive(e) {
+			t.Error("created entity should not be alive")
+		}
+		if !ecs.Alive(existing) {
+			t.Error("deleted entity should be alive")
+		}
+		if l := len(ecsys.All[skeleton.Skeleton](ecs)); l != 0 {
+			t.Errorf("All() len = %d, want 0", l)
+		}
+		if l := len(ecsys.All[velocity.Velocity](ecs)); l != 0 {
+			t.Errorf("All[velocity.Velocity]() len = %d, want 0", l)
+		}
+		if c := ecs.Children(existing); len(c) != 0 {
+			t.Errorf("Children() = %v, want none", c)
+		}
+		if _, err = ecsys.Get[position.Position](ecs, existing); err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+		// The index of the rolled back entity is handed out again.
+		if next, err := ecs.CreateEmptyEntity(); err != nil || next != e {
+			t.Errorf("CreateEmptyEntity() = %v, %v, want %v", next, err, e)
+		}
+	})
+
+	t.Run("should roll back and re-panic if fn panics", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Tx() should panic")
+				}
+			}()
+			_ = ecs.Tx(func(tx *ecsys.ECS) error {
+				if _, err := tx.CreateEntity(tx.Root(), point.Zero()); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+		if l := len(ecsys.All[position.Position](ecs)); l != 0 {
+			t.Errorf("All() len = %d, want 0", l)
+		}
+		if err := ecs.Tx(func(*ecsys.ECS) error { return nil }); err != nil {
+			t.Errorf("Tx() after panic error = %v", err)
+		}
+	})
+
+	t.Run("should not allow nested transactions", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		err := ecs.Tx(func(tx *ecsys.ECS) error {
+			return tx.Tx(func(*ecsys.ECS) error { return nil })
+		})
+		if !errors.Is(err, ecsys.ErrStagingInProgress) {
+			t.Errorf("Tx() should return ErrStagingInProgress, got %v", err)
+		}
+	})
+	t.Run("should run next to another system", func(t *testing.T) {
+		bus := event.NewBus()
+		ecs := ecsys.New(bus, ecsys.NewStores())
+		var published atomic.Int64
+		bus.Subscribe(event.MatchAll(), func(event.Event) error {
+			published.Add(1)
+			return nil
+		})
+
+		const n = 50
+		errRollback := errors.New("roll back")
+		var created, committed []entity.Entity
+		var wg sync.WaitGroup
+		wg.Add(3)
+		// A system that changes the ECS outside of a transaction.
+		go func() {
+			defer wg.Done()
+			for range n {
+				e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+				if err != nil {
+					t.Errorf("CreateEntity() error = %v", err)
+					return
+				}
+				if err = ecs.InsertResource(resource.NewCamera()); err != nil {
+					t.Errorf("InsertResource() error = %v", err)
+					return
+				}
+				created = append(created, e)
+			}
+		}()
+		// Transactions that are rolled back.
+		go func() {
+			defer wg.Done()
+			for range n {
+				err := ecs.Tx(func(tx *ecsys.ECS) error {
+					e, err := tx.CreateEntity(tx.Root(), point.Zero())
+					if err != nil {
+						return err
+					}
+					if _, err = ecsys.Add(tx, *skeleton.New(e)); err != nil {
+						return err
+					}
+					return errRollback
+				})
+				if !errors.Is(err, errRollback) {
+					t.Errorf("Tx() error = %v, want %v", err, errRollback)
+				}
+			}
+		}()
+		// Transactions that are committed.
+		go func() {
+			defer wg.Done()
+			for range n {
+				var e entity.Entity
+				err := ecs.Tx(func(tx *ecsys.ECS) error {
+					var err error
+					e, err = tx.CreateEntity(tx.Root(), point.Zero())
+					return err
+				})
+				if err != nil {
+					t.Errorf("Tx() error = %v", err)
+					return
+				}
+				committed = append(committed, e)
+			}
+		}()
+		wg.Wait()
+
+		for _, e := range append(created, committed...) {
+			if _, err := ecsys.Get[position.Position](ecs, e); err != nil {
+				t.Errorf("Get(%v) error = %v", e, err)
+			}
+		}
+		st := ecs.Stats()
+		if st.Entities != 2*n {
+			t.Errorf("Entities = %d, want %d", st.Entities, 2*n)
+		}
+		if sk, _ := st.Store(skeleton.Type); sk.Components != 0 {
+			t.Errorf("skeletons = %d, want 0", sk.Components)
+		}
+		// The other system publishes an entity, position and resource event per iteration, the committed
+		// transactions an entity and position event.
+		if got := published.Load(); got != 5*n {
+			t.Errorf("published = %d, want %d", got, 5*n)
+		}
+	})
+	t.Run("should not overwrite the changes of another transaction on rollback", func(t *testing.T) {
+		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
+		e, err := ecs.CreateEntity(ecs.Root(), point.Zero())
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		v := velocity.New(e, point.Zero())
+		if v.I, err = ecsys.Add(ecs, *v); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		const n = 200
+		errRollback := errors.New("roll back")
+		// Every rolled back transaction waits with its rollback until the other transaction
+		// wrote the component and was committed.
+		written, committed := make(chan struct{}), make(chan struct{})
+		write := func(tx *ecsys.ECS, p point.Point) error {
+			c := *v
+			c.Point = p
+			return ecsys.Update(tx, c)
+		}
+		var wg sync.WaitGroup
+		wg.Add(2)
+		// Transactions that write the component and are rolled back.
+		go func() {
+			defer wg.Done()
+			for range n {
+				err := ecs.Tx(func(tx *ecsys.ECS) error {
+					if err := write(tx, point.New(-1, -1)); err != nil {
+						return err
+					}
+					written <- struct{}{}
+					<-committed
+					return errRollback
+				})
+				if !errors.Is(err, errRollback) {
+					t.Errorf("Tx() error = %v, want %v", err, errRollback)
+				}
+			}
+		}()
+		// Transactions that write the same component and are committed.
+		go func() {
+			defer wg.Done()
+			for i := range n {
+				<-written
+				if err := ecs.Tx(func(tx *ecsys.ECS) error { return write(tx, point.New(i, 0)) }); err != nil {
+					t.Errorf("Tx() error = %v", err)
+				}
+				committed <- struct{}{}
+			}
+		}()
+		wg.Wait()
+
+		got, err := ecsys.Get[velocity.Velocity](ecs, e)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if want := point.New(n-1, 0); got.Point != want {
+			t.Errorf("Point = %v, want %v, the last committed value", got.Point, want)
+		}
+	})
+}
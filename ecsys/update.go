@@ -1,75 +1,71 @@
 package ecsys
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dwethmar/vork/component"
-	"github.com/dwethmar/vork/component/controllable"
-	"github.com/dwethmar/vork/component/position"
-	"github.com/dwethmar/vork/component/shape"
-	"github.com/dwethmar/vork/component/skeleton"
-	"github.com/dwethmar/vork/component/sprite"
-	"github.com/dwethmar/vork/component/velocity"
-	"github.com/dwethmar/vork/event"
 )
 
-func updateComponent[T component.Component](
-	eventBus *event.Bus,
-	c component.Component,
-	store Store[T],
-	eventCreator func(T) event.Event,
-) error {
-	comp, ok := c.(T)
-	if !ok {
-		return fmt.Errorf("expected component of type %T, got %T", *new(T), c)
-	}
-	if err := store.Update(comp); err != nil {
-		return fmt.Errorf("could not update component: %w", err)
-	}
-	if eventCreator != nil {
-		if err := eventBus.Publish(eventCreator(comp)); err != nil {
-			return fmt.Errorf("could not publish update event: %w", err)
-		}
-	}
-	return nil
-}
-
-func (s *ECS) UpdatePositionComponent(c position.Position) error {
-	err := updateComponent(s.eventBus, &c, s.stores.Position, func(p *position.Position) event.Event {
-		return position.NewUpdatedEvent(*p)
-	})
+// Update updates an existing component of a registered type in the ECS.
+func Update[C any, T ComponentPointer[C]](s *ECS, c C) error {
+	r, err := lookup[T]()
 	if err != nil {
 		return err
 	}
-	// Add the entity to the hierarchy.
-	if err = s.hierarchy.Update(c.Parent, c.Entity()); err != nil {
-		return fmt.Errorf("could not update entity in hierarchy: %w", err)
-	}
-	return nil
+	return updateComponent(s, r, T(&c))
 }
 
-func (s *ECS) UpdateVelocityComponent(c velocity.Velocity) error {
-	return updateComponent(s.eventBus, &c, s.stores.Velocity, func(p *velocity.Velocity) event.Event {
-		return velocity.NewUpdatedEvent(*p)
-	})
+func updateComponent[T component.Component](ecs *ECS, r Registration[T], comp T) error {
+	return writeComponent(ecs, r, comp, true)
 }
 
-func (s *ECS) UpdateControllableComponent(c controllable.Controllable) error {
-	return updateComponent(s.eventBus, &c, s.stores.Controllable, func(ctr *controllable.Controllable) event.Event {
-		return controllable.NewUpdatedEvent(*ctr)
+// writeComponent updates the component in its store. The update event is published right away
+// if publish is set, otherwise the component is marked dirty and its event is published by EndFrame.
+func writeComponent[T component.Component](ecs *ECS, r Registration[T], comp T, publish bool) error {
+	store, err := StoreFor[T](ecs.stores)
+	if err != nil {
+		return err
+	}
+	var old T
+	_, err = ecs.owners.of(r.Type).write(ecs.staging, func() (uint, error) {
+		if old, err = store.Get(comp.ID()); err != nil {
+			return 0, fmt.Errorf("could not update component: %w", err)
+		}
+		if err = store.Update(comp); err != nil {
+			return 0, fmt.Errorf("could not update component: %w", err)
+		}
+		if err = ecs.indexes.add(r.Type, comp); err != nil {
+			if uErr := store.Update(old); uErr != nil {
+				return 0, errors.Join(err, uErr)
+			}
+			return 0, fmt.Errorf("could not update component: %w", err)
+		}
+		return comp.ID(), nil
 	})
-}
-
-func (s *ECS) UpdateRectangleComponent(c shape.Rectangle) error {
-	return updateComponent(s.eventBus, &c, s.stores.Rectangle, nil)
-}
-
-func (s *ECS) UpdateSpriteComponent(c sprite.Sprite) error {
-	return updateComponent(s.eventBus, &c, s.stores.Sprite, nil)
-}
-
-func (s *ECS) UpdateSkeletonComponent(c skeleton.Skeleton) error {
-	return updateComponent(s.eventBus, &c, s.stores.Skeleton, func(sk *skeleton.Skeleton) event.Event {
-		return skeleton.NewUpdatedEvent(*sk)
+	if err != nil {
+		return err
+	}
+	ecs.recordComponentUndo(r.Type, comp.ID(), func() error {
+		if err := store.Update(old); err != nil {
+			return err
+		}
+		return ecs.indexes.add(r.Type, old)
 	})
+	if !publish {
+		ecs.recordComponentUndo(r.Type, comp.ID(), ecs.changes.markDirty(r.Type, comp.ID()))
+	} else {
+		ecs.recordComponentUndo(r.Type, comp.ID(), ecs.changes.touch(r.Type, comp.ID()))
+		if r.UpdatedEvent != nil {
+			if err = ecs.publish(r.UpdatedEvent(comp)); err != nil {
+				return fmt.Errorf("could not publish update event: %w", err)
+			}
+		}
+	}
+	if r.AfterUpdate != nil {
+		if err = r.AfterUpdate(ecs, comp); err != nil {
+			return err
+		}
+	}
+	return ecs.notifyChange(r.Type, old, r.clone(comp))
 }
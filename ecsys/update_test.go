@@ -3,6 +3,7 @@ package ecsys_test
 import (
 	"testing"
 
+	"github.com/dwethmar/vork/component/position"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
@@ -10,7 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
-func TestECS_UpdatePositionComponent(t *testing.T) {
+func TestUpdate_Position(t *testing.T) {
 	t.Run("should update a position component", func(t *testing.T) {
 		ecs := ecsys.New(event.NewBus(), ecsys.NewStores())
 
@@ -24,7 +25,7 @@ func TestECS_UpdatePositionComponent(t *testing.T) {
 			t.Errorf("Error creating entity: %s", err)
 		}
 
-		pos, err := ecs.GetPosition(child1)
+		pos, err := ecsys.Get[position.Position](ecs, child1)
 		if err != nil {
 			t.Errorf("Error getting position: %s", err)
 		}
@@ -36,7 +37,7 @@ func TestECS_UpdatePositionComponent(t *testing.T) {
 		pos.Parent = ecs.Root()
 
 		// move child 1 to root
-		if err = ecs.UpdatePositionComponent(pos); err != nil {
+		if err = ecsys.Update(ecs, pos); err != nil {
 			t.Errorf("Error updating position: %s", err)
 		}
 
@@ -1,5 +1,32 @@
 // package entity is a package that holds the entity type.
 package entity
 
+import "fmt"
+
+// generationShift is the number of bits used for the index of an entity.
+const generationShift = 32
+
 // Entity is a type that represents an entity.
-type Entity uint
+// The lower 32 bits hold the index of the entity and the upper 32 bits its generation.
+// The generation is incremented every time an index is reused, so a handle to
+// a deleted entity can be told apart from the entity that reuses its index.
+type Entity uint64
+
+// New creates an entity from an index and a generation.
+func New(index, generation uint32) Entity {
+	return Entity(uint64(generation)<<generationShift | uint64(index))
+}
+
+// Index returns the index of the entity.
+func (e Entity) Index() uint32 { return uint32(e) }
+
+// Generation returns the generation of the entity.
+func (e Entity) Generation() uint32 { return uint32(e >> generationShift) }
+
+// String returns the index of the entity, followed by its generation if it is not zero.
+func (e Entity) String() string {
+	if e.Generation() == 0 {
+		return fmt.Sprintf("%d", e.Index())
+	}
+	return fmt.Sprintf("%dv%d", e.Index(), e.Generation())
+}
@@ -0,0 +1,29 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/dwethmar/vork/entity"
+)
+
+func TestNew(t *testing.T) {
+	e := entity.New(42, 7)
+	if e.Index() != 42 {
+		t.Errorf("Index() = %d, want 42", e.Index())
+	}
+	if e.Generation() != 7 {
+		t.Errorf("Generation() = %d, want 7", e.Generation())
+	}
+	if entity.New(42, 0) != entity.Entity(42) {
+		t.Errorf("New(42, 0) = %d, want 42", entity.New(42, 0))
+	}
+}
+
+func TestEntity_String(t *testing.T) {
+	if s := entity.New(3, 0).String(); s != "3" {
+		t.Errorf("String() = %q, want %q", s, "3")
+	}
+	if s := entity.New(3, 1).String(); s != "3v1" {
+		t.Errorf("String() = %q, want %q", s, "3v1")
+	}
+}
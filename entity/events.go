@@ -0,0 +1,38 @@
+package entity
+
+import "github.com/dwethmar/vork/event"
+
+const (
+	// CreatedEventType is the event type for when an entity is created.
+	CreatedEventType = "entity.created"
+	// DeletedEventType is the event type for when an entity is deleted.
+	DeletedEventType = "entity.deleted"
+)
+
+var (
+	_ event.Event = &CreatedEvent{}
+	_ event.Event = &DeletedEvent{}
+)
+
+// CreatedEvent is published when an entity is created.
+// The entity holds the generation of its index, so a reused index can be told apart.
+type CreatedEvent struct {
+	Entity Entity
+}
+
+func NewCreatedEvent(e Entity) *CreatedEvent {
+	return &CreatedEvent{Entity: e}
+}
+
+func (e *CreatedEvent) Event() string { return CreatedEventType }
+
+// DeletedEvent is published when an entity is deleted, after its components have been deleted.
+type DeletedEvent struct {
+	Entity Entity
+}
+
+func NewDeletedEvent(e Entity) *DeletedEvent {
+	return &DeletedEvent{Entity: e}
+}
+
+func (e *DeletedEvent) Event() string { return DeletedEventType }
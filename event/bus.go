@@ -1,6 +1,16 @@
 package event
 
-import "sync"
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ErrConsumed is returned by a handler to consume the event, the handlers after it are not
+// called and Publish returns no error.
+var ErrConsumed = errors.New("event consumed")
 
 // Event is an interface that requires implementing the Event method.
 type Event interface {
@@ -12,16 +22,68 @@ type Handler func(Event) error
 
 // Subscription is a struct that represents a handler subscribed to a specific matching.
 type Subscription struct {
-	id      int
-	matcher Matcher
-	handler Handler
+	id       int
+	matcher  Matcher
+	handler  Handler
+	priority int
+	name     string
+	logged   bool // errors of the handler are logged instead of returned
+}
+
+// ID returns the identifier of the subscription.
+func (s Subscription) ID() int { return s.id }
+
+// Name returns the name of the subscription, empty if it has none.
+func (s Subscription) Name() string { return s.name }
+
+// Priority returns the priority of the subscription.
+func (s Subscription) Priority() int { return s.priority }
+
+// Matcher returns the matcher of the subscription.
+func (s Subscription) Matcher() Matcher { return s.matcher }
+
+// SubscribeOption configures a subscription.
+type SubscribeOption func(*Subscription)
+
+// WithName names the subscription. The name is part of the errors of its handler.
+func WithName(name string) SubscribeOption {
+	return func(s *Subscription) { s.name = name }
+}
+
+// WithPriority sets the priority of the subscription. Handlers with a higher priority are
+// called first, handlers with the same priority in the order they subscribed. The default is 0.
+func WithPriority(priority int) SubscribeOption {
+	return func(s *Subscription) { s.priority = priority }
+}
+
+// WithLoggedErrors makes the bus log the errors of the handler instead of returning them, whatever
+// the error policy. It is meant for handlers that are not critical, so their failure does not stop
+// the other handlers or the change that published the event.
+func WithLoggedErrors() SubscribeOption {
+	return func(s *Subscription) { s.logged = true }
 }
 
 // Bus is a struct that manages event handlers in a thread-safe manner.
 type Bus struct {
 	mu       sync.RWMutex
 	handlers []Subscription
-	nextID   int // Used to assign a unique ID to each handler
+	index    *index // Handlers by event type, used to dispatch events
+	nextID   int    // Used to assign a unique ID to each handler
+
+	countsMu sync.Mutex
+	counts   map[string]int // Number of published events per event type
+
+	queueMu   sync.Mutex
+	mode      Mode
+	immediate map[string]bool // Event types that skip the queue
+	maxDepth  int
+	queue     []queued
+	flushing  bool
+	depth     int // Depth of the queued event that is being dispatched
+
+	policyMu sync.Mutex
+	policy   ErrorPolicy
+	logger   *slog.Logger // Logs the errors of handlers with the LogErrors policy
 }
 
 // NewBus creates and returns a new Bus instance.
@@ -29,23 +91,41 @@ func NewBus() *Bus {
 	return &Bus{
 		mu:       sync.RWMutex{},
 		handlers: []Subscription{},
+		index:    newIndex(nil),
 		nextID:   1, // Start IDs from 1
+		counts:   map[string]int{},
+
+		immediate: map[string]bool{},
+		maxDepth:  DefaultMaxDepth,
+
+		logger: slog.Default(),
 	}
 }
 
 // Subscribe adds a new handler function to the Bus for a specific event type.
+// The handler is called before the handlers with a lower priority, see WithPriority.
 // It returns an identifier for the handler, which can be used to unsubscribe it later.
-func (b *Bus) Subscribe(m Matcher, handler Handler) int {
+func (b *Bus) Subscribe(m Matcher, handler Handler, opts ...SubscribeOption) int {
+	sub := Subscription{
+		matcher: m,
+		handler: handler,
+	}
+	for _, opt := range opts {
+		opt(&sub)
+	}
+
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	id := b.nextID
+	sub.id = b.nextID
 	b.nextID++
-	b.handlers = append(b.handlers, Subscription{
-		id:      id,
-		matcher: m,
-		handler: handler,
-	})
-	return id
+	// keep the handlers sorted by priority, after the handlers with the same priority
+	i := len(b.handlers)
+	for i > 0 && b.handlers[i-1].priority < sub.priority {
+		i--
+	}
+	b.handlers = slices.Insert(b.handlers, i, sub)
+	b.index = newIndex(b.handlers)
+	return sub.id
 }
 
 // Unsubscribe removes a handler function from the Bus for a specific event type using its identifier.
@@ -56,6 +136,7 @@ func (b *Bus) Unsubscribe(id int) {
 	for i, entry := range b.handlers {
 		if entry.id == id {
 			b.handlers = append(b.handlers[:i], b.handlers[i+1:]...)
+			b.index = newIndex(b.handlers)
 			break
 		}
 	}
@@ -69,21 +150,58 @@ func (b *Bus) Subscriptions() []Subscription {
 	return subscriptions
 }
 
+// PublishCounts returns the number of published events per event type.
+func (b *Bus) PublishCounts() map[string]int {
+	b.countsMu.Lock()
+	defer b.countsMu.Unlock()
+	return maps.Clone(b.counts)
+}
+
 // Publish sends an event to all the handlers subscribed to the event's type.
+// In Queued mode the event is added to the queue instead, see Flush.
+// What it returns when handlers fail depends on the error policy, see SetErrorPolicy, and
+// on the subscriptions that log their errors, see WithLoggedErrors.
 func (b *Bus) Publish(event Event) error {
+	b.countsMu.Lock()
+	b.counts[event.Event()]++
+	b.countsMu.Unlock()
+
+	if ok, err := b.enqueue(event); ok || err != nil {
+		return err
+	}
+	return b.dispatch(event)
+}
+
+// dispatch calls the handlers that match the event by priority, until a handler consumes the
+// event. Only the handlers indexed for the type of the event and those that can match any
+// event are matched. The errors of the handlers are wrapped in a HandlerError and handled by the error policy.
+func (b *Bus) dispatch(event Event) error {
 	b.mu.RLock()
-	handlers := make([]Subscription, len(b.handlers))
-	copy(handlers, b.handlers)
+	handlers := b.index.candidates(event.Event())
 	b.mu.RUnlock()
 
+	policy, logger := b.errorPolicy()
+	var errs []error
 	for _, entry := range handlers {
 		if !entry.matcher.Match(event) {
 			continue
 		}
-		if err := entry.handler(event); err != nil {
-			return err
+		err := entry.handler(event)
+		if errors.Is(err, ErrConsumed) {
+			break
+		}
+		if err != nil {
+			hErr := &HandlerError{ID: entry.id, Name: entry.name, Event: event.Event(), Err: err}
+			if entry.logged {
+				logger.Error("event handler failed", slog.Any("error", hErr))
+				continue
+			}
+			errs = append(errs, hErr)
+			if policy == StopOnError {
+				break
+			}
 		}
 	}
 
-	return nil
+	return b.handle(errs)
 }
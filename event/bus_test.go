@@ -132,3 +132,142 @@ func TestBus_Subscriptions(t *testing.T) {
 		}
 	})
 }
+
+func TestBus_PublishCounts(t *testing.T) {
+	t.Run("count published events per event type", func(t *testing.T) {
+		bus := event.NewBus()
+		for _, e := range []string{"a", "b", "a"} {
+			if err := bus.Publish(&MockEvent{event: e}); err != nil {
+				t.Errorf("Bus.Publish() error = %v", err)
+			}
+		}
+
+		counts := bus.PublishCounts()
+		if counts["a"] != 2 || counts["b"] != 1 || len(counts) != 2 {
+			t.Errorf("Bus.PublishCounts() = %v, want map[a:2 b:1]", counts)
+		}
+	})
+}
+
+func TestBus_Priority(t *testing.T) {
+	t.Run("call handlers by priority, then in subscription order", func(t *testing.T) {
+		bus := event.NewBus()
+		var got []string
+		subscribe := func(name string, priority int) {
+			bus.Subscribe(event.MatchAny("testEvent"), func(event.Event) error {
+				got = append(got, name)
+				return nil
+			}, event.WithPriority(priority))
+		}
+		subscribe("default", 0)
+		subscribe("low", -1)
+		subscribe("high", 10)
+		subscribe("default2", 0)
+
+		if err := bus.Publish(&MockEvent{event: "testEvent"}); err != nil {
+			t.Fatalf("Bus.Publish() error = %v", err)
+		}
+		want := []string{"high", "default", "default2", "low"}
+		if len(got) != len(want) {
+			t.Fatalf("handlers called = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("handlers called = %v, want %v", got, want)
+				break
+			}
+		}
+	})
+
+	t.Run("a consumed event does not reach lower priority handlers", func(t *testing.T) {
+		bus := event.NewBus()
+		worldCalled := false
+		bus.Subscribe(event.MatchAny("click"), func(event.Event) error {
+			worldCalled = true
+			return nil
+		})
+		bus.Subscribe(event.MatchAny("click"), func(event.Event) error {
+			return event.ErrConsumed
+		}, event.WithPriority(1))
+
+		if err := bus.Publish(&MockEvent{event: "click"}); err != nil {
+			t.Errorf("Bus.Publish() error = %v, want nil", err)
+		}
+		if worldCalled {
+			t.Errorf("handler with a lower priority was called for a consumed event")
+		}
+	})
+}
+
+// countingMatcher counts how often it is asked to match an event.
+type countingMatcher struct {
+	event.TypeMatcher
+	calls int
+}
+
+func (m *countingMatcher) Match(e event.Event) bool {
+	m.calls++
+	return m.TypeMatcher.Match(e)
+}
+
+func TestBus_Index(t *testing.T) {
+	t.Run("do not run indexed matchers for other event types", func(t *testing.T) {
+		bus := event.NewBus()
+		m := &countingMatcher{TypeMatcher: event.MatchAny("testEvent")}
+		bus.Subscribe(m, func(event.Event) error { return nil })
+
+		for _, name := range []string{"otherEvent", "testEvent"} {
+			if err := bus.Publish(&MockEvent{event: name}); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if m.calls != 1 {
+			t.Errorf("expected the matcher to run once, got %d", m.calls)
+		}
+	})
+
+	t.Run("do not run typed matchers for other event types", func(t *testing.T) {
+		bus := event.NewBus()
+		m := &countingMatcher{TypeMatcher: event.MatchAny("other")}
+		bus.Subscribe(event.And(event.MatchType[OtherEvent](), event.MatcherFunc(m.Match)), func(event.Event) error { return nil })
+
+		for _, e := range []event.Event{&MockEvent{event: "testEvent"}, OtherEvent{}} {
+			if err := bus.Publish(e); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if m.calls != 1 {
+			t.Errorf("expected the matcher to run once, got %d", m.calls)
+		}
+	})
+
+	t.Run("keep the order of indexed and other handlers", func(t *testing.T) {
+		bus := event.NewBus()
+		var got []string
+		subscribe := func(name string, m event.Matcher, opts ...event.SubscribeOption) {
+			bus.Subscribe(m, func(event.Event) error {
+				got = append(got, name)
+				return nil
+			}, opts...)
+		}
+		subscribe("any", event.MatchAll())
+		subscribe("indexed", event.MatchAny("testEvent"))
+		subscribe("glob", event.MatchGlob("test*"))
+		subscribe("first", event.MatchAny("testEvent"), event.WithPriority(1))
+		subscribe("other", event.MatchAny("otherEvent"))
+
+		if err := bus.Publish(&MockEvent{event: "testEvent"}); err != nil {
+			t.Fatalf("Bus.Publish() error = %v", err)
+		}
+		want := []string{"first", "any", "indexed", "glob"}
+		if len(got) != len(want) {
+			t.Fatalf("handlers called = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("handlers called = %v, want %v", got, want)
+				break
+			}
+		}
+	})
+}
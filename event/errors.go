@@ -0,0 +1,75 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrorPolicy decides what the bus does when a handler returns an error.
+type ErrorPolicy int
+
+const (
+	// StopOnError returns the first error, the handlers after it are not called.
+	StopOnError ErrorPolicy = iota
+	// JoinErrors calls all handlers and returns their errors joined with errors.Join.
+	JoinErrors
+	// LogErrors calls all handlers, logs their errors and returns no error.
+	LogErrors
+)
+
+// HandlerError is the error of a handler, with the subscription that failed.
+type HandlerError struct {
+	ID    int    // ID of the subscription.
+	Name  string // Name of the subscription, empty if it has none.
+	Event string // Type of the event the handler was called for.
+	Err   error
+}
+
+func (e *HandlerError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("handler %d failed on event %s: %v", e.ID, e.Event, e.Err)
+	}
+	return fmt.Sprintf("handler %d (%s) failed on event %s: %v", e.ID, e.Name, e.Event, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// SetErrorPolicy sets what Publish and Flush do when a handler returns an error.
+// A new bus stops on the first error.
+func (b *Bus) SetErrorPolicy(p ErrorPolicy) {
+	b.policyMu.Lock()
+	defer b.policyMu.Unlock()
+	b.policy = p
+}
+
+// SetLogger sets the logger used by the LogErrors policy. A new bus logs to slog.Default().
+func (b *Bus) SetLogger(logger *slog.Logger) {
+	b.policyMu.Lock()
+	defer b.policyMu.Unlock()
+	b.logger = logger
+}
+
+// errorPolicy returns the error policy and the logger of the bus.
+func (b *Bus) errorPolicy() (ErrorPolicy, *slog.Logger) {
+	b.policyMu.Lock()
+	defer b.policyMu.Unlock()
+	return b.policy, b.logger
+}
+
+// handle applies the error policy to the errors of the handlers that were called for an event.
+func (b *Bus) handle(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	policy, logger := b.errorPolicy()
+	if policy != LogErrors {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		logger.Error("event handler failed", slog.Any("error", err))
+	}
+	return nil
+}
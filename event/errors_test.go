@@ -0,0 +1,123 @@
+package event_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/dwethmar/vork/event"
+)
+
+// subscribeFailing subscribes handlers that fail with the errors and returns how often they were called.
+func subscribeFailing(bus *event.Bus, errs ...error) *int {
+	calls := 0
+	for i, err := range errs {
+		bus.Subscribe(event.MatchAny("testEvent"), func(event.Event) error {
+			calls++
+			return err
+		}, event.WithName("handler"+string(rune('a'+i))))
+	}
+	return &calls
+}
+
+func TestBus_SetErrorPolicy(t *testing.T) {
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+
+	t.Run("stop on the first error", func(t *testing.T) {
+		bus := event.NewBus()
+		calls := subscribeFailing(bus, errA, errB)
+		err := bus.Publish(&MockEvent{event: "testEvent"})
+		if !errors.Is(err, errA) || errors.Is(err, errB) {
+			t.Errorf("Bus.Publish() error = %v, want only %v", err, errA)
+		}
+		if *calls != 1 {
+			t.Errorf("expected 1 call, got %d", *calls)
+		}
+	})
+
+	t.Run("join the errors of all handlers", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetErrorPolicy(event.JoinErrors)
+		calls := subscribeFailing(bus, errA, nil, errB)
+		err := bus.Publish(&MockEvent{event: "testEvent"})
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("Bus.Publish() error = %v, want %v and %v", err, errA, errB)
+		}
+		if *calls != 3 {
+			t.Errorf("expected 3 calls, got %d", *calls)
+		}
+	})
+
+	t.Run("log the errors and continue", func(t *testing.T) {
+		bus := event.NewBus()
+		var buf bytes.Buffer
+		bus.SetErrorPolicy(event.LogErrors)
+		bus.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+		calls := subscribeFailing(bus, errA, errB)
+		if err := bus.Publish(&MockEvent{event: "testEvent"}); err != nil {
+			t.Errorf("Bus.Publish() error = %v, want nil", err)
+		}
+		if *calls != 2 {
+			t.Errorf("expected 2 calls, got %d", *calls)
+		}
+		if !strings.Contains(buf.String(), errA.Error()) || !strings.Contains(buf.String(), errB.Error()) {
+			t.Errorf("expected both errors to be logged, got %q", buf.String())
+		}
+	})
+
+	t.Run("log the errors of a subscription and continue", func(t *testing.T) {
+		bus := event.NewBus()
+		var buf bytes.Buffer
+		bus.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+		calls := 0
+		bus.Subscribe(event.MatchAny("testEvent"), func(event.Event) error {
+			calls++
+			return errA
+		}, event.WithName("optional"), event.WithLoggedErrors())
+		calls2 := subscribeFailing(bus, errB)
+		err := bus.Publish(&MockEvent{event: "testEvent"})
+		if errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("Bus.Publish() error = %v, want only %v", err, errB)
+		}
+		if calls != 1 || *calls2 != 1 {
+			t.Errorf("expected both handlers to be called once, got %d and %d", calls, *calls2)
+		}
+		if !strings.Contains(buf.String(), errA.Error()) || !strings.Contains(buf.String(), "optional") {
+			t.Errorf("expected the error of the subscription to be logged, got %q", buf.String())
+		}
+	})
+
+	t.Run("wrap the errors with the subscription", func(t *testing.T) {
+		bus := event.NewBus()
+		subscribeFailing(bus, errA)
+		err := bus.Publish(&MockEvent{event: "testEvent"})
+		var hErr *event.HandlerError
+		if !errors.As(err, &hErr) {
+			t.Fatalf("Bus.Publish() error = %v, want a HandlerError", err)
+		}
+		sub := bus.Subscriptions()[0]
+		if hErr.ID != sub.ID() || hErr.Name != "handlera" || hErr.Event != "testEvent" {
+			t.Errorf("HandlerError = %+v, want ID %d, name handlera and event testEvent", hErr, sub.ID())
+		}
+	})
+
+	t.Run("flush all queued events when joining errors", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetMode(event.Queued)
+		bus.SetErrorPolicy(event.JoinErrors)
+		calls := subscribeFailing(bus, errA)
+		for range 2 {
+			if err := bus.Publish(&MockEvent{event: "testEvent"}); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if err := bus.Flush(); !errors.Is(err, errA) {
+			t.Errorf("Bus.Flush() error = %v, want %v", err, errA)
+		}
+		if *calls != 2 || bus.Pending() != 0 {
+			t.Errorf("expected 2 calls and no pending events, got %d calls and %d pending", *calls, bus.Pending())
+		}
+	})
+}
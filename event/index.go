@@ -0,0 +1,56 @@
+package event
+
+// index holds the subscriptions of a bus by the event types their matchers can match.
+// It is rebuilt when the subscriptions change and never modified, so it can be read
+// without holding the lock of the bus.
+type index struct {
+	byType map[string][]indexed // Subscriptions whose matchers only match known event types.
+	any    []indexed            // Subscriptions whose matchers can match any event.
+}
+
+// indexed is a subscription with its position in the subscriptions of the bus.
+type indexed struct {
+	Subscription
+	order int
+}
+
+// newIndex indexes the subscriptions, which are ordered by priority.
+func newIndex(subs []Subscription) *index {
+	ix := &index{byType: map[string][]indexed{}}
+	for i, sub := range subs {
+		entry := indexed{Subscription: sub, order: i}
+		types, ok := eventTypes(sub.matcher)
+		if !ok {
+			ix.any = append(ix.any, entry)
+			continue
+		}
+		for _, t := range types {
+			ix.byType[t] = append(ix.byType[t], entry)
+		}
+	}
+	return ix
+}
+
+// candidates returns the subscriptions that can match events of the event type, ordered by priority.
+func (ix *index) candidates(eventType string) []indexed {
+	typed := ix.byType[eventType]
+	if len(typed) == 0 {
+		return ix.any
+	}
+	if len(ix.any) == 0 {
+		return typed
+	}
+	merged := make([]indexed, 0, len(typed)+len(ix.any))
+	i, j := 0, 0
+	for i < len(typed) && j < len(ix.any) {
+		if typed[i].order < ix.any[j].order {
+			merged = append(merged, typed[i])
+			i++
+		} else {
+			merged = append(merged, ix.any[j])
+			j++
+		}
+	}
+	merged = append(merged, typed[i:]...)
+	return append(merged, ix.any[j:]...)
+}
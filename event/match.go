@@ -1,6 +1,9 @@
 package event
 
-import "slices"
+import (
+	"slices"
+	"strings"
+)
 
 // Matcher is an interface that defines a method to match events.
 type Matcher interface {
@@ -8,6 +11,14 @@ type Matcher interface {
 	Match(e Event) bool
 }
 
+// Indexer is implemented by matchers that can tell which event types they match. The bus
+// indexes their subscriptions by event type, so Publish does not run them for other events.
+type Indexer interface {
+	// EventTypes returns the event types the matcher can match, or false if it can match
+	// events of any type.
+	EventTypes() ([]string, bool)
+}
+
 // MatcherFunc is a function that implements the Matcher interface.
 type MatcherFunc func(e Event) bool
 
@@ -16,6 +27,150 @@ func (f MatcherFunc) Match(e Event) bool {
 	return f(e)
 }
 
-func MatchAny(t ...string) MatcherFunc {
-	return func(e Event) bool { return slices.Contains(t, e.Event()) }
+// MatchAll returns a matcher that matches every event.
+func MatchAll() MatcherFunc {
+	return func(Event) bool { return true }
+}
+
+// TypeMatcher matches events of the event types.
+type TypeMatcher []string
+
+// Match returns true if the type of the event is one of the event types.
+func (m TypeMatcher) Match(e Event) bool { return slices.Contains(m, e.Event()) }
+
+// EventTypes returns the event types.
+func (m TypeMatcher) EventTypes() ([]string, bool) { return m, true }
+
+// MatchAny returns a matcher that matches events of any of the event types.
+func MatchAny(t ...string) TypeMatcher {
+	return TypeMatcher(t)
+}
+
+// MatchPrefix returns a matcher that matches events whose type starts with one of the prefixes.
+func MatchPrefix(prefixes ...string) MatcherFunc {
+	return func(e Event) bool {
+		return slices.ContainsFunc(prefixes, func(p string) bool { return strings.HasPrefix(e.Event(), p) })
+	}
+}
+
+// globMatcher matches event types against patterns.
+type globMatcher []string
+
+// MatchGlob returns a matcher that matches events whose type matches one of the patterns.
+// A * in a pattern matches any sequence of characters, so "position.*" matches all
+// position events and "*.deleted" all deleted events.
+func MatchGlob(patterns ...string) Matcher {
+	return globMatcher(patterns)
+}
+
+func (m globMatcher) Match(e Event) bool {
+	return slices.ContainsFunc(m, func(p string) bool { return glob(p, e.Event()) })
+}
+
+// EventTypes returns the patterns if none of them has a wildcard.
+func (m globMatcher) EventTypes() ([]string, bool) {
+	if slices.ContainsFunc(m, func(p string) bool { return strings.Contains(p, "*") }) {
+		return nil, false
+	}
+	return m, true
+}
+
+// glob reports whether s matches the pattern, where * matches any sequence of characters.
+func glob(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	first, last := parts[0], parts[len(parts)-1]
+	if !strings.HasPrefix(s, first) {
+		return false
+	}
+	s = s[len(first):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
+
+// andMatcher matches events that match all its matchers.
+type andMatcher []Matcher
+
+// And returns a matcher that matches events that match all the matchers.
+func And(matchers ...Matcher) Matcher {
+	return andMatcher(matchers)
+}
+
+func (m andMatcher) Match(e Event) bool {
+	for _, mm := range m {
+		if !mm.Match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// EventTypes returns the event types all indexed matchers have in common.
+func (m andMatcher) EventTypes() ([]string, bool) {
+	var types []string
+	indexed := false
+	for _, mm := range m {
+		t, ok := eventTypes(mm)
+		if !ok {
+			continue
+		}
+		if !indexed {
+			types, indexed = slices.Clone(t), true
+			continue
+		}
+		types = slices.DeleteFunc(types, func(s string) bool { return !slices.Contains(t, s) })
+	}
+	return types, indexed
+}
+
+// orMatcher matches events that match any of its matchers.
+type orMatcher []Matcher
+
+// Or returns a matcher that matches events that match any of the matchers.
+func Or(matchers ...Matcher) Matcher {
+	return orMatcher(matchers)
+}
+
+func (m orMatcher) Match(e Event) bool {
+	for _, mm := range m {
+		if mm.Match(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventTypes returns the event types of all matchers if they are all indexed.
+func (m orMatcher) EventTypes() ([]string, bool) {
+	var types []string
+	for _, mm := range m {
+		t, ok := eventTypes(mm)
+		if !ok {
+			return nil, false
+		}
+		types = append(types, t...)
+	}
+	slices.Sort(types)
+	return slices.Compact(types), true
+}
+
+// Not returns a matcher that matches events that do not match the matcher.
+func Not(m Matcher) MatcherFunc {
+	return func(e Event) bool { return !m.Match(e) }
+}
+
+// eventTypes returns the event types the matcher can match, or false if it can match any event.
+func eventTypes(m Matcher) ([]string, bool) {
+	if ix, ok := m.(Indexer); ok {
+		return ix.EventTypes()
+	}
+	return nil, false
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/dwethmar/vork/event"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestMatcherFunc_Match(t *testing.T) {
@@ -31,3 +32,104 @@ func TestMatchAny(t *testing.T) {
 		}
 	})
 }
+
+func TestMatchPrefix(t *testing.T) {
+	matcher := event.MatchPrefix("position.", "input.")
+	for name, want := range map[string]bool{
+		"position.created": true,
+		"input.clicked":    true,
+		"velocity.created": false,
+		"position":         false,
+	} {
+		if got := matcher.Match(&MockEvent{event: name}); got != want {
+			t.Errorf("Match(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		event   string
+		want    bool
+	}{
+		{"position.*", "position.created", true},
+		{"position.*", "velocity.created", false},
+		{"*.deleted", "hitbox.deleted", true},
+		{"*.deleted", "hitbox.created", false},
+		{"*", "anything", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "acb", false},
+		{"ab*b", "ab", false},
+		{"position.created", "position.created", true},
+		{"position.created", "position.updated", false},
+	}
+	for _, tt := range tests {
+		if got := event.MatchGlob(tt.pattern).Match(&MockEvent{event: tt.event}); got != tt.want {
+			t.Errorf("MatchGlob(%s).Match(%s) = %v, want %v", tt.pattern, tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	created := event.MatchGlob("*.created")
+	position := event.MatchPrefix("position.")
+	tests := []struct {
+		name    string
+		matcher event.Matcher
+		event   string
+		want    bool
+	}{
+		{"And matches if all match", event.And(created, position), "position.created", true},
+		{"And does not match if one does not match", event.And(created, position), "velocity.created", false},
+		{"Or matches if one matches", event.Or(created, position), "velocity.created", true},
+		{"Or does not match if none match", event.Or(created, position), "velocity.deleted", false},
+		{"Not inverts the matcher", event.Not(position), "velocity.deleted", true},
+		{"Not inverts the matcher", event.Not(position), "position.deleted", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Match(&MockEvent{event: tt.event}); got != tt.want {
+				t.Errorf("Match(%s) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexer(t *testing.T) {
+	tests := []struct {
+		name      string
+		matcher   event.Matcher
+		want      []string
+		wantIndex bool
+	}{
+		{"MatchAny", event.MatchAny("a", "b"), []string{"a", "b"}, true},
+		{"MatchGlob without wildcards", event.MatchGlob("a", "b"), []string{"a", "b"}, true},
+		{"MatchGlob with wildcards", event.MatchGlob("a.*"), nil, false},
+		{"Or of indexed matchers", event.Or(event.MatchAny("b", "a"), event.MatchAny("a", "c")), []string{"a", "b", "c"}, true},
+		{"Or with a matcher of any event", event.Or(event.MatchAny("a"), event.MatchAll()), nil, false},
+		{"And of indexed matchers", event.And(event.MatchAny("a", "b"), event.MatchAny("b", "c")), []string{"b"}, true},
+		{"And with a matcher of any event", event.And(event.MatchAny("a"), event.MatchPrefix("a")), []string{"a"}, true},
+		{"And of matchers of any event", event.And(event.MatchAll(), event.MatchPrefix("a")), nil, false},
+		{"And passes the index of an indexed matcher", event.And(event.MatchAll(), event.MatchType[OtherEvent]()), []string{"other"}, true},
+		{"MatchType of a concrete type", event.MatchType[OtherEvent](), []string{"other"}, true},
+		{"MatchType of an interface", event.MatchType[event.Event](), nil, false},
+		{"MatchType of a type without a fixed event type", event.MatchType[*MockEvent](), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			ok := false
+			if ix, isIndexer := tt.matcher.(event.Indexer); isIndexer {
+				got, ok = ix.EventTypes()
+			}
+			if ok != tt.wantIndex {
+				t.Fatalf("EventTypes() ok = %v, want %v", ok, tt.wantIndex)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("EventTypes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxDepth is the maximum cascade depth of a new bus, see SetMaxDepth.
+const DefaultMaxDepth = 8
+
+// ErrMaxDepth is returned when an event is published beyond the maximum cascade depth.
+var ErrMaxDepth = errors.New("event cascade exceeds max depth")
+
+// Mode decides when the handlers of a published event are called.
+type Mode int
+
+const (
+	// Immediate calls the handlers from Publish, before it returns.
+	Immediate Mode = iota
+	// Queued buffers the events until Flush is called.
+	Queued
+)
+
+// queued is an event waiting for the next flush.
+type queued struct {
+	event Event
+	depth int // Number of events that led to this event being published.
+}
+
+// SetMode sets when the handlers of published events are called. A new bus is Immediate.
+// Switching to Immediate does not dispatch the events that are already queued.
+func (b *Bus) SetMode(m Mode) {
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	b.mode = m
+}
+
+// SetImmediate makes events of the event types skip the queue, their handlers are
+// always called from Publish. Use it for events whose handlers must see the world
+// exactly as it was when the event was published.
+func (b *Bus) SetImmediate(eventTypes ...string) {
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	for _, t := range eventTypes {
+		b.immediate[t] = true
+	}
+}
+
+// SetMaxDepth sets how many times events may be published by handlers of queued events
+// before Publish returns ErrMaxDepth. It stops handlers from publishing each other forever.
+func (b *Bus) SetMaxDepth(depth int) {
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	b.maxDepth = depth
+}
+
+// Pending returns the number of queued events.
+func (b *Bus) Pending() int {
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	return len(b.queue)
+}
+
+// enqueue adds the event to the queue, it returns false if the event has to be dispatched now.
+// Events published while a queued event is dispatched are one level deeper than that event.
+func (b *Bus) enqueue(event Event) (bool, error) {
+	b.queueMu.Lock()
+	defer b.queueMu.Unlock()
+	if b.mode != Queued || b.immediate[event.Event()] {
+		return false, nil
+	}
+	depth := 0
+	if b.flushing {
+		depth = b.depth + 1
+	}
+	if depth > b.maxDepth {
+		return true, fmt.Errorf("could not queue event %s at depth %d: %w", event.Event(), depth, ErrMaxDepth)
+	}
+	b.queue = append(b.queue, queued{event: event, depth: depth})
+	return true, nil
+}
+
+// Flush dispatches the queued events in the order they were published. Events published
+// by their handlers are queued behind them and dispatched by the same flush.
+// With the StopOnError policy it stops at the first error and the events that were not
+// dispatched stay queued, with JoinErrors it dispatches all events and joins the errors.
+// Calling Flush from a handler does nothing, the running flush dispatches the events.
+func (b *Bus) Flush() error {
+	b.queueMu.Lock()
+	if b.flushing {
+		b.queueMu.Unlock()
+		return nil
+	}
+	b.flushing = true
+	b.queueMu.Unlock()
+
+	defer func() {
+		b.queueMu.Lock()
+		b.flushing = false
+		b.queueMu.Unlock()
+	}()
+
+	var errs []error
+	for {
+		b.queueMu.Lock()
+		if len(b.queue) == 0 {
+			b.queueMu.Unlock()
+			return errors.Join(errs...)
+		}
+		q := b.queue[0]
+		b.queue = b.queue[1:]
+		b.depth = q.depth
+		b.queueMu.Unlock()
+
+		if err := b.dispatch(q.event); err != nil {
+			if policy, _ := b.errorPolicy(); policy == StopOnError {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+}
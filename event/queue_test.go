@@ -0,0 +1,124 @@
+package event_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwethmar/vork/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBus_Flush(t *testing.T) {
+	t.Run("should dispatch queued events in publish order", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetMode(event.Queued)
+		var got []string
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(e event.Event) error {
+			got = append(got, e.Event())
+			return nil
+		})
+
+		for _, name := range []string{"a", "b", "c"} {
+			if err := bus.Publish(&MockEvent{event: name}); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if len(got) != 0 || bus.Pending() != 3 {
+			t.Fatalf("expected 3 pending events and no calls, got %d pending and calls %v", bus.Pending(), got)
+		}
+		if err := bus.Flush(); err != nil {
+			t.Fatalf("Bus.Flush() error = %v", err)
+		}
+		if diff := cmp.Diff([]string{"a", "b", "c"}, got); diff != "" {
+			t.Errorf("dispatched events mismatch (-want +got):\n%s", diff)
+		}
+		if bus.Pending() != 0 {
+			t.Errorf("Bus.Pending() = %d, want 0", bus.Pending())
+		}
+	})
+
+	t.Run("should dispatch immediate event types from Publish", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetMode(event.Queued)
+		bus.SetImmediate("now")
+		var got []string
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(e event.Event) error {
+			got = append(got, e.Event())
+			return nil
+		})
+
+		for _, name := range []string{"later", "now"} {
+			if err := bus.Publish(&MockEvent{event: name}); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if diff := cmp.Diff([]string{"now"}, got); diff != "" {
+			t.Errorf("dispatched events mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should dispatch events published by handlers behind the queue", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetMode(event.Queued)
+		var got []string
+		bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(e event.Event) error {
+			got = append(got, e.Event())
+			if e.Event() == "a" {
+				return bus.Publish(&MockEvent{event: "a2"})
+			}
+			return nil
+		})
+
+		for _, name := range []string{"a", "b"} {
+			if err := bus.Publish(&MockEvent{event: name}); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if err := bus.Flush(); err != nil {
+			t.Fatalf("Bus.Flush() error = %v", err)
+		}
+		if diff := cmp.Diff([]string{"a", "b", "a2"}, got); diff != "" {
+			t.Errorf("dispatched events mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should stop cascades at the max depth", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetMode(event.Queued)
+		bus.SetMaxDepth(3)
+		calls := 0
+		bus.Subscribe(event.MatchAny("loop"), func(event.Event) error {
+			calls++
+			return bus.Publish(&MockEvent{event: "loop"})
+		})
+
+		if err := bus.Publish(&MockEvent{event: "loop"}); err != nil {
+			t.Fatalf("Bus.Publish() error = %v", err)
+		}
+		if err := bus.Flush(); !errors.Is(err, event.ErrMaxDepth) {
+			t.Errorf("Bus.Flush() error = %v, want %v", err, event.ErrMaxDepth)
+		}
+		if calls != 4 {
+			t.Errorf("expected 4 calls, got %d", calls)
+		}
+	})
+
+	t.Run("should keep the events that were not dispatched", func(t *testing.T) {
+		bus := event.NewBus()
+		bus.SetMode(event.Queued)
+		errHandler := errors.New("handler failed")
+		bus.Subscribe(event.MatchAny("fail"), func(event.Event) error { return errHandler })
+
+		for _, name := range []string{"fail", "ok"} {
+			if err := bus.Publish(&MockEvent{event: name}); err != nil {
+				t.Fatalf("Bus.Publish() error = %v", err)
+			}
+		}
+		if err := bus.Flush(); !errors.Is(err, errHandler) {
+			t.Errorf("Bus.Flush() error = %v, want %v", err, errHandler)
+		}
+		if bus.Pending() != 1 {
+			t.Errorf("Bus.Pending() = %d, want 1", bus.Pending())
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package event
+
+import "reflect"
+
+// typeMatcher matches events of type T, see MatchType.
+type typeMatcher[T Event] struct{}
+
+// MatchType returns a matcher that matches events of type T. If T is an interface,
+// it matches the events that implement it.
+//
+// If T is a concrete type, the matcher is indexed by the event type its zero value returns,
+// so every event of T must have the same event type. Types whose zero value has no event
+// type, or whose Event method cannot be called on it, are not indexed.
+func MatchType[T Event]() Matcher {
+	return typeMatcher[T]{}
+}
+
+// Match returns true if the event is of type T.
+func (typeMatcher[T]) Match(e Event) bool {
+	_, ok := e.(T)
+	return ok
+}
+
+// EventTypes returns the event type of the zero value of T, or false if T is an interface.
+func (typeMatcher[T]) EventTypes() (types []string, ok bool) {
+	if reflect.TypeFor[T]().Kind() == reflect.Interface {
+		return nil, false
+	}
+	defer func() {
+		if recover() != nil {
+			types, ok = nil, false
+		}
+	}()
+	var zero T
+	if t := zero.Event(); t != "" {
+		return []string{t}, true
+	}
+	return nil, false
+}
+
+// Subscribe adds a handler for the events of type T to the bus. The handler receives the
+// typed event, so it does not need to match event names or assert the type itself.
+// It returns an identifier for the handler, which can be used to unsubscribe it later.
+func Subscribe[T Event](b *Bus, handler func(T) error, opts ...SubscribeOption) int {
+	return b.Subscribe(MatchType[T](), func(e Event) error {
+		return handler(e.(T)) //nolint: forcetypeassert // matched by MatchType
+	}, opts...)
+}
@@ -0,0 +1,76 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/dwethmar/vork/event"
+)
+
+// OtherEvent is a second implementation of the Event interface for testing.
+type OtherEvent struct{}
+
+func (e OtherEvent) Event() string { return "other" }
+
+func TestSubscribe(t *testing.T) {
+	t.Run("should only pass events of the type to the handler", func(t *testing.T) {
+		bus := event.NewBus()
+		var got []string
+		event.Subscribe(bus, func(e *MockEvent) error {
+			got = append(got, e.event)
+			return nil
+		})
+
+		for _, e := range []event.Event{&MockEvent{event: "a"}, OtherEvent{}, &MockEvent{event: "b"}} {
+			if err := bus.Publish(e); err != nil {
+				t.Errorf("Bus.Publish() error = %v", err)
+			}
+		}
+		if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("handled events = %v, want [a b]", got)
+		}
+	})
+
+	t.Run("should pass events that implement an interface type", func(t *testing.T) {
+		bus := event.NewBus()
+		handled := 0
+		event.Subscribe(bus, func(event.Event) error {
+			handled++
+			return nil
+		})
+		for _, e := range []event.Event{&MockEvent{event: "a"}, OtherEvent{}} {
+			if err := bus.Publish(e); err != nil {
+				t.Errorf("Bus.Publish() error = %v", err)
+			}
+		}
+		if handled != 2 {
+			t.Errorf("handled %d events, want 2", handled)
+		}
+	})
+
+	t.Run("should index the subscription by the event type", func(t *testing.T) {
+		bus := event.NewBus()
+		event.Subscribe(bus, func(OtherEvent) error { return nil })
+		subs := bus.Subscriptions()
+		if len(subs) != 1 {
+			t.Fatalf("expected 1 subscription, got %d", len(subs))
+		}
+		ix, ok := subs[0].Matcher().(event.Indexer)
+		if !ok {
+			t.Fatalf("expected the matcher to be an Indexer")
+		}
+		types, ok := ix.EventTypes()
+		if !ok || len(types) != 1 || types[0] != "other" {
+			t.Errorf("EventTypes() = %v, %v, want [other], true", types, ok)
+		}
+	})
+}
+
+func TestMatchType(t *testing.T) {
+	m := event.MatchType[OtherEvent]()
+	if m.Match(&MockEvent{event: "other"}) {
+		t.Errorf("MatchType() matched an event of another type")
+	}
+	if !m.Match(OtherEvent{}) {
+		t.Errorf("MatchType() did not match an event of the type")
+	}
+}
@@ -19,6 +19,9 @@ type Config struct {
 // New returns true if the Config struct is new.
 func (c *Config) New() bool { return c.new }
 
+// JournalPath returns the path of the event journal of the save.
+func (c *Config) JournalPath() string { return filepath.Join(c.saveFolder, "journal.jsonl") }
+
 // New creates a new Config struct with default values.
 func New(saveName string, parentFolder string) *Config {
 	// Compute the save folder path
@@ -13,6 +13,21 @@ func debugHierarchy(h *ecsys.ECS) {
 	renderHierarchy(h, h.Root(), "", true)
 }
 
+// debugDiff prints the differences between the world at the last save and the world now.
+func debugDiff(saved *ecsys.Snapshot, ecs *ecsys.ECS) {
+	fmt.Print(ecsys.Diff(saved, ecs.Snapshot()))
+}
+
+// debugStats prints the statistics of the world as JSON.
+func debugStats(ecs *ecsys.ECS) error {
+	b, err := ecs.Stats().JSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode stats: %w", err)
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
 // renderHierarchy recursively prints the hierarchy of entities in the ECS.
 func renderHierarchy(h *ecsys.ECS, e entity.Entity, prefix string, isLast bool) {
 	// Choose the appropriate branch character
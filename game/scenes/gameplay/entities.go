@@ -4,40 +4,65 @@ import (
 	"fmt"
 
 	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/name"
 	"github.com/dwethmar/vork/component/skeleton"
+	"github.com/dwethmar/vork/component/tags"
 	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/point"
 )
 
+const (
+	playerName = "player" // name of the player entity
+	enemyTag   = "enemy"  // tag of enemy entities
+)
+
+// addPlayer adds a player to the ECS. Nothing is added if one of the components cannot be added.
 func addPlayer(parent entity.Entity, ecs *ecsys.ECS, p point.Point) (entity.Entity, error) {
-	e, err := ecs.CreateEntity(parent, p)
-	if err != nil {
-		return e, fmt.Errorf("could not create entity: %w", err)
-	}
-	if _, err = ecs.AddSkeleton(*skeleton.New(e)); err != nil {
-		return e, fmt.Errorf("could not add skeleton: %w", err)
-	}
-	if _, err = ecs.AddControllable(*controllable.New(e)); err != nil {
-		return e, fmt.Errorf("could not add controllable: %w", err)
-	}
-	if _, err = ecs.AddVelocity(*velocity.New(e, point.Zero())); err != nil {
-		return e, fmt.Errorf("could not add velocity component to entity %v: %w", e, err)
-	}
-	return e, nil
+	var e entity.Entity
+	err := ecs.Tx(func(tx *ecsys.ECS) error {
+		var err error
+		e, err = tx.CreateEntity(parent, p)
+		if err != nil {
+			return fmt.Errorf("could not create entity: %w", err)
+		}
+		if _, err = ecsys.Add(tx, *skeleton.New(e)); err != nil {
+			return fmt.Errorf("could not add skeleton: %w", err)
+		}
+		if _, err = ecsys.Add(tx, *controllable.New(e)); err != nil {
+			return fmt.Errorf("could not add controllable: %w", err)
+		}
+		if _, err = ecsys.Add(tx, *name.New(e, playerName)); err != nil {
+			return fmt.Errorf("could not add name: %w", err)
+		}
+		if _, err = ecsys.Add(tx, *velocity.New(e, point.Zero())); err != nil {
+			return fmt.Errorf("could not add velocity component to entity %v: %w", e, err)
+		}
+		return nil
+	})
+	return e, err
 }
 
+// addEnemy adds an enemy to the ECS. Nothing is added if one of the components cannot be added.
 func addEnemy(parent entity.Entity, ecs *ecsys.ECS, p point.Point) (entity.Entity, error) {
-	e, err := ecs.CreateEntity(parent, p)
-	if err != nil {
-		return e, fmt.Errorf("could not create entity: %w", err)
-	}
-	if _, err = ecs.AddSkeleton(*skeleton.New(e)); err != nil {
-		return e, fmt.Errorf("could not add skeleton: %w", err)
-	}
-	if _, err = ecs.AddVelocity(*velocity.New(e, point.Zero())); err != nil {
-		return e, fmt.Errorf("could not add velocity component to entity %v: %w", e, err)
-	}
-	return e, nil
+	var e entity.Entity
+	err := ecs.Tx(func(tx *ecsys.ECS) error {
+		var err error
+		e, err = tx.CreateEntity(parent, p)
+		if err != nil {
+			return fmt.Errorf("could not create entity: %w", err)
+		}
+		if _, err = ecsys.Add(tx, *skeleton.New(e)); err != nil {
+			return fmt.Errorf("could not add skeleton: %w", err)
+		}
+		if _, err = ecsys.Add(tx, *velocity.New(e, point.Zero())); err != nil {
+			return fmt.Errorf("could not add velocity component to entity %v: %w", e, err)
+		}
+		if _, err = ecsys.Add(tx, *tags.New(e, enemyTag)); err != nil {
+			return fmt.Errorf("could not add tags: %w", err)
+		}
+		return nil
+	})
+	return e, err
 }
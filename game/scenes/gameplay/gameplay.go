@@ -3,14 +3,18 @@ package gameplay
 import (
 	"fmt"
 	"log/slog"
+	"os"
 	"time"
 
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/event"
 	"github.com/dwethmar/vork/event/mouse"
 	"github.com/dwethmar/vork/game"
+	"github.com/dwethmar/vork/journal"
 	"github.com/dwethmar/vork/persistence"
 	"github.com/dwethmar/vork/point"
+	"github.com/dwethmar/vork/resource"
+	"github.com/dwethmar/vork/scheduler"
 	"github.com/dwethmar/vork/sprites"
 	"github.com/dwethmar/vork/spritesheet"
 	"github.com/dwethmar/vork/systems/collision"
@@ -47,15 +51,23 @@ func onHoverHandler() func(x, y int) {
 type GamePlay struct {
 	logger      *slog.Logger
 	db          *bbolt.DB
+	eventBus    *event.Bus
 	systems     []System
+	scheduler   *scheduler.Scheduler
 	ecs         *ecsys.ECS
 	persistence *persistence.Persistance
+	saved       *ecsys.Snapshot // world at the last save, used by the diff debug command
+	journal     *journal.Writer
+	journalFile *os.File
+	frame       uint64 // number of the current frame, recorded in the journal
 }
 
 // New creates a new game play scene.
 func New(logger *slog.Logger, saveName string, s *spritesheet.Spritesheet) (*GamePlay, error) {
 	logger = logger.With("scene", "gameplay")
 	eventBus := event.NewBus()
+	// handlers stop the frame on error, non-critical subscribers opt into logging their errors
+	eventBus.SetLogger(logger.With("system", "events"))
 	stores := ecsys.NewStores()
 	ecs := ecsys.New(eventBus, stores)
 	persistence := persistence.New(persistence.Options{
@@ -84,16 +96,30 @@ func New(logger *slog.Logger, saveName string, s *spritesheet.Spritesheet) (*Gam
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
 
+	if err = insertResources(ecs, cfg); err != nil {
+		return nil, fmt.Errorf("failed to insert resources: %w", err)
+	}
+
 	// check if it is an existing save
 	if err = setupGame(logger, persistence, ecs, db); err != nil {
 		return nil, fmt.Errorf("failed to setup game: %w", err)
 	}
 
+	// record the events of the session, the world matches the save at its start
+	journalFile, err := journal.Open(cfg.JournalPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	journalWriter := journal.NewWriter(journalFile)
+	if err = journalWriter.StartSession(); err != nil {
+		return nil, fmt.Errorf("failed to start journal session: %w", err)
+	}
+	journalWriter.Attach(eventBus)
+
 	systems := []System{
 		keyinput.New(keyinput.Options{
-			Logger:              logger,
-			ECS:                 ecs,
-			VelocityScaleFactor: 5,
+			Logger: logger,
+			ECS:    ecs,
 		}),
 		render.New(render.Options{
 			Logger:       logger,
@@ -104,12 +130,10 @@ func New(logger *slog.Logger, saveName string, s *spritesheet.Spritesheet) (*Gam
 		}),
 		skeletons.New(logger, ecs, eventBus),
 		collision.New(collision.Options{
-			Logger:              logger,
-			ECS:                 ecs,
-			EventBus:            eventBus,
-			VelocityScaleFactor: 5,
-			Friction:            4,
-			VelocityThreshold:   1,
+			Logger:            logger,
+			ECS:               ecs,
+			EventBus:          eventBus,
+			VelocityThreshold: 1,
 		}),
 	}
 	// init all systems after loading the game to make sure all components are loaded
@@ -118,12 +142,20 @@ func New(logger *slog.Logger, saveName string, s *spritesheet.Spritesheet) (*Gam
 			return nil, fmt.Errorf("failed to init system %T: %w", sys, err)
 		}
 	}
+	// from here on the events of a frame are dispatched at the end of the frame,
+	// the systems have set up what existed before they subscribed in Init.
+	eventBus.SetMode(event.Queued)
 	return &GamePlay{
 		logger:      logger,
 		db:          db,
+		eventBus:    eventBus,
 		systems:     systems,
+		scheduler:   scheduler.New(schedulable(systems)...),
 		ecs:         ecs,
 		persistence: persistence,
+		saved:       ecs.Snapshot(),
+		journal:     journalWriter,
+		journalFile: journalFile,
 	}, nil
 }
 
@@ -172,23 +204,54 @@ func (s *GamePlay) Draw(screen *ebiten.Image) error {
 
 // Update updates the game.
 func (s *GamePlay) Update() error {
+	s.frame++
+	s.journal.SetFrame(s.frame)
 	// check if F5 is pressed
 	if inpututil.IsKeyJustPressed(ebiten.KeyF5) {
 		started := time.Now()
 		if err := s.persistence.Save(s.db); err != nil {
 			return fmt.Errorf("failed to save game: %w", err)
 		}
+		s.saved = s.ecs.Snapshot()
+		// the events from here on are replayed on the new save
+		if err := s.journal.StartSession(); err != nil {
+			return fmt.Errorf("failed to start journal session: %w", err)
+		}
 		s.logger.Info("game saved", slog.Duration("duration", time.Since(started)))
 		return nil
 	}
 	if inpututil.IsKeyJustPressed(ebiten.KeyF9) {
 		debugHierarchy(s.ecs)
 	}
-	for _, sys := range s.systems {
-		if err := sys.Update(); err != nil {
-			return fmt.Errorf("failed to update system %T: %w", sys, err)
+	if inpututil.IsKeyJustPressed(ebiten.KeyF10) {
+		debugDiff(s.saved, s.ecs)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyF11) {
+		if err := debugStats(s.ecs); err != nil {
+			return err
 		}
 	}
+	if err := ecsys.UpdateResource(s.ecs, func(t *resource.Time) bool {
+		t.Advance(time.Second / time.Duration(ebiten.TPS()))
+		return true
+	}); err != nil {
+		return fmt.Errorf("failed to advance time: %w", err)
+	}
+	// systems that do not access the same components or resources are updated concurrently
+	if err := s.scheduler.Update(); err != nil {
+		return err
+	}
+	// publish the change events of the components that were marked dirty during the frame
+	if err := s.ecs.EndFrame(); err != nil {
+		return fmt.Errorf("failed to end frame: %w", err)
+	}
+	// dispatch the events published during the frame, including the change events above
+	if err := s.eventBus.Flush(); err != nil {
+		return fmt.Errorf("failed to dispatch events: %w", err)
+	}
+	if err := s.journal.Flush(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
 	return nil
 }
 
@@ -202,6 +265,12 @@ func (s *GamePlay) Close() error {
 	if err := s.db.Close(); err != nil {
 		return fmt.Errorf("failed to close db: %w", err)
 	}
+	if err := s.journal.Flush(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := s.journalFile.Close(); err != nil {
+		return fmt.Errorf("failed to close journal: %w", err)
+	}
 	return nil
 }
 
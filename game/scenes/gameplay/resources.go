@@ -0,0 +1,36 @@
+package gameplay
+
+import (
+	"fmt"
+
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/game/scenes/gameplay/config"
+	"github.com/dwethmar/vork/resource"
+)
+
+// insertResources inserts the global resources with their default values.
+// The persistent resources are overwritten by their saved values when an existing game is loaded.
+func insertResources(ecs *ecsys.ECS, cfg *config.Config) error {
+	resources := []struct {
+		resource resource.Resource
+		opts     []ecsys.ResourceOption
+	}{
+		{resource: resource.NewCamera(), opts: []ecsys.ResourceOption{ecsys.Persistent()}},
+		{resource: resource.Time{}},
+		{resource: resource.InputState{}},
+		{
+			resource: resource.GameSettings{
+				SaveName:            cfg.SaveName,
+				VelocityScaleFactor: 5,
+				Friction:            4,
+			},
+			opts: []ecsys.ResourceOption{ecsys.Persistent()},
+		},
+	}
+	for _, r := range resources {
+		if err := ecs.InsertResource(r.resource, r.opts...); err != nil {
+			return fmt.Errorf("failed to insert %s resource: %w", r.resource.Type(), err)
+		}
+	}
+	return nil
+}
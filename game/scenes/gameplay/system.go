@@ -1,6 +1,7 @@
 package gameplay
 
 import (
+	"github.com/dwethmar/vork/scheduler"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -11,3 +12,12 @@ type System interface {
 	Update() error
 	Close() error
 }
+
+// schedulable converts the systems to systems that can be scheduled.
+func schedulable(systems []System) []scheduler.System {
+	s := make([]scheduler.System, len(systems))
+	for i, sys := range systems {
+		s[i] = sys
+	}
+	return s
+}
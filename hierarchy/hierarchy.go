@@ -3,6 +3,8 @@ package hierarchy
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/dwethmar/vork/entity"
 )
@@ -14,7 +16,10 @@ var (
 	ErrCyclicRelationship = errors.New("cannot add parent as a child of its descendant")
 )
 
+// Hierarchy keeps track of the parent and children of entities.
+// It is safe for concurrent use.
 type Hierarchy struct {
+	mu       sync.RWMutex
 	root     entity.Entity
 	parents  map[entity.Entity]entity.Entity   // child -> parent
 	children map[entity.Entity][]entity.Entity // parent -> []children
@@ -40,6 +45,8 @@ func (h *Hierarchy) clear() {
 }
 
 func (h *Hierarchy) Build(pairs []EntityPair) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.clear()
 	// Temporary tree to store relationships before hierarchy is built
 	tree := make(map[entity.Entity][]entity.Entity)
@@ -66,7 +73,7 @@ func (h *Hierarchy) addToParent(parent entity.Entity, tree map[entity.Entity][]e
 	// Add each child to the hierarchy under the current parent
 	for _, child := range children {
 		// Add the child under the current parent
-		if err := h.Add(parent, child); err != nil {
+		if err := h.add(parent, child); err != nil {
 			return fmt.Errorf("error adding child %v to parent %v: %w", child, parent, err)
 		}
 
@@ -97,6 +104,12 @@ func (h *Hierarchy) Root() entity.Entity {
 }
 
 func (h *Hierarchy) Add(parent entity.Entity, child entity.Entity) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.add(parent, child)
+}
+
+func (h *Hierarchy) add(parent entity.Entity, child entity.Entity) error {
 	// Check if the parent exists in the hierarchy
 	if !h.entityExists(parent) {
 		return fmt.Errorf("parent %v does not exist in the hierarchy", parent)
@@ -116,6 +129,8 @@ func (h *Hierarchy) Add(parent entity.Entity, child entity.Entity) error {
 }
 
 func (h *Hierarchy) Update(parent entity.Entity, child entity.Entity) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	// check if update is necessary
 	if parent == h.parents[child] {
 		return nil
@@ -141,6 +156,8 @@ func (h *Hierarchy) Update(parent entity.Entity, child entity.Entity) error {
 
 // Delete removes an entity and all its descendants from the hierarchy.
 func (h *Hierarchy) Delete(child entity.Entity) []entity.Entity {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	// Check if the entity exists
 	if !h.entityExists(child) {
 		return nil
@@ -164,6 +181,8 @@ func (h *Hierarchy) Delete(child entity.Entity) []entity.Entity {
 }
 
 func (h *Hierarchy) Parent(child entity.Entity) (entity.Entity, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	if child == h.root {
 		return 0, fmt.Errorf("root %v has no parent", h.root)
 	}
@@ -174,8 +193,34 @@ func (h *Hierarchy) Parent(child entity.Entity) (entity.Entity, error) {
 	return parent, nil
 }
 
+// Children returns a copy of the children of the entity.
 func (h *Hierarchy) Children(parent entity.Entity) []entity.Entity {
-	return h.children[parent]
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return slices.Clone(h.children[parent])
+}
+
+// Descendants returns the children of the entity, their children and so on.
+func (h *Hierarchy) Descendants(e entity.Entity) []entity.Entity {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.collectDescendants(e)
+}
+
+// Depth returns the number of levels below the root, 0 if the root has no children.
+func (h *Hierarchy) Depth() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.depth(h.root)
+}
+
+// Helper method to find the number of levels below an entity.
+func (h *Hierarchy) depth(e entity.Entity) int {
+	d := 0
+	for _, child := range h.children[e] {
+		d = max(d, h.depth(child)+1)
+	}
+	return d
 }
 
 // Helper method to check if there's a path from 'from' to 'to' (used for cycle detection).
@@ -225,6 +225,44 @@ func TestHierarchy_Children(t *testing.T) {
 	})
 }
 
+func TestHierarchy_Descendants(t *testing.T) {
+	t.Run("should return the children and their descendants", func(t *testing.T) {
+		root := entity.Entity(0)
+		h := hierarchy.New(root)
+		pairs := [][2]entity.Entity{{root, 1}, {1, 2}, {2, 3}, {1, 4}, {root, 5}}
+		for _, p := range pairs {
+			if err := h.Add(p[0], p[1]); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+		if diff := cmp.Diff([]entity.Entity{2, 3, 4}, h.Descendants(1)); diff != "" {
+			t.Errorf("Descendants() mismatch (-want +got):\n%s", diff)
+		}
+		if got := h.Descendants(5); len(got) != 0 {
+			t.Errorf("Descendants() = %v, want none", got)
+		}
+	})
+}
+
+func TestHierarchy_Depth(t *testing.T) {
+	t.Run("should return the number of levels below the root", func(t *testing.T) {
+		root := entity.Entity(0)
+		h := hierarchy.New(root)
+		if d := h.Depth(); d != 0 {
+			t.Errorf("Depth() = %d, want 0", d)
+		}
+		pairs := [][2]entity.Entity{{root, 1}, {1, 2}, {2, 3}, {root, 4}}
+		for _, p := range pairs {
+			if err := h.Add(p[0], p[1]); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+		}
+		if d := h.Depth(); d != 3 {
+			t.Errorf("Depth() = %d, want 3", d)
+		}
+	})
+}
+
 func TestHierarchy_Root(t *testing.T) {
 	t.Run("Root should return the root entity", func(t *testing.T) {
 		root := entity.Entity(0)
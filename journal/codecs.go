@@ -0,0 +1,143 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/dwethmar/vork/component/active"
+	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/name"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/relation"
+	"github.com/dwethmar/vork/component/skeleton"
+	"github.com/dwethmar/vork/component/tags"
+	"github.com/dwethmar/vork/component/transform"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/event/mouse"
+)
+
+// codec decodes and replays the events of an event type.
+type codec struct {
+	decode func(data []byte) (event.Event, error)
+	replay func(s *ecsys.ECS, bus *event.Bus, data []byte) error
+}
+
+// codecs holds the codecs per event type.
+var codecs = map[string]codec{
+	entity.CreatedEventType:         entityCodec(func(e *entity.CreatedEvent) entity.Entity { return e.Entity }, (*ecsys.ECS).RecreateEntity),
+	entity.DeletedEventType:         entityCodec(func(e *entity.DeletedEvent) entity.Entity { return e.Entity }, (*ecsys.ECS).DeleteEntity),
+	position.CreatedEventType:       componentCodec(position.NewCreatedEvent, add[position.Position]),
+	position.UpdatedEventType:       componentCodec(position.NewUpdatedEvent, ecsys.Update[position.Position]),
+	position.DeletedEventType:       componentCodec(position.NewDeletedEvent, remove[position.Position]),
+	velocity.CreatedEventType:       componentCodec(velocity.NewCreatedEvent, add[velocity.Velocity]),
+	velocity.UpdatedEventType:       componentCodec(velocity.NewUpdatedEvent, ecsys.Update[velocity.Velocity]),
+	velocity.DeletedEventType:       componentCodec(velocity.NewDeletedEvent, remove[velocity.Velocity]),
+	skeleton.CreatedEventType:       componentCodec(skeleton.NewCreatedEvent, add[skeleton.Skeleton]),
+	skeleton.UpdatedEventType:       componentCodec(skeleton.NewUpdatedEvent, ecsys.Update[skeleton.Skeleton]),
+	skeleton.DeletedEventType:       componentCodec(skeleton.NewDeletedEvent, remove[skeleton.Skeleton]),
+	controllable.CreatedEventType:   componentCodec(controllable.NewCreatedEvent, add[controllable.Controllable]),
+	controllable.UpdatedEventType:   componentCodec(controllable.NewUpdatedEvent, ecsys.Update[controllable.Controllable]),
+	controllable.DeletedEventType:   componentCodec(controllable.NewDeletedEvent, remove[controllable.Controllable]),
+	hitbox.CreatedEventType:         componentCodec(hitbox.NewCreatedEvent, add[hitbox.Hitbox]),
+	hitbox.UpdatedEventType:         componentCodec(hitbox.NewUpdatedEvent, ecsys.Update[hitbox.Hitbox]),
+	hitbox.DeletedEventType:         componentCodec(hitbox.NewDeletedEvent, remove[hitbox.Hitbox]),
+	name.CreatedEventType:           componentCodec(name.NewCreatedEvent, add[name.Name]),
+	name.UpdatedEventType:           componentCodec(name.NewUpdatedEvent, ecsys.Update[name.Name]),
+	name.DeletedEventType:           componentCodec(name.NewDeletedEvent, remove[name.Name]),
+	tags.CreatedEventType:           componentCodec(tags.NewCreatedEvent, add[tags.Tags]),
+	tags.UpdatedEventType:           componentCodec(tags.NewUpdatedEvent, ecsys.Update[tags.Tags]),
+	tags.DeletedEventType:           componentCodec(tags.NewDeletedEvent, remove[tags.Tags]),
+	relation.CreatedEventType:       componentCodec(relation.NewCreatedEvent, add[relation.Relation]),
+	relation.UpdatedEventType:       componentCodec(relation.NewUpdatedEvent, ecsys.Update[relation.Relation]),
+	relation.DeletedEventType:       componentCodec(relation.NewDeletedEvent, remove[relation.Relation]),
+	transform.CreatedEventType:      componentCodec(transform.NewCreatedEvent, add[transform.Transform]),
+	transform.UpdatedEventType:      componentCodec(transform.NewUpdatedEvent, ecsys.Update[transform.Transform]),
+	transform.DeletedEventType:      componentCodec(transform.NewDeletedEvent, remove[transform.Transform]),
+	active.CreatedEventType:         componentCodec(active.NewCreatedEvent, add[active.Active]),
+	active.UpdatedEventType:         componentCodec(active.NewUpdatedEvent, ecsys.Update[active.Active]),
+	active.DeletedEventType:         componentCodec(active.NewDeletedEvent, remove[active.Active]),
+	mouse.LeftMouseClickedEventType: eventCodec[mouse.LeftClickedEvent](),
+}
+
+// componentCodec returns the codec of a component event. Replaying the event applies the
+// change to the ECS, which publishes the event again.
+func componentCodec[C any, E event.Event](newEvent func(C) E, apply func(*ecsys.ECS, C) error) codec {
+	return codec{
+		decode: func(data []byte) (event.Event, error) {
+			var c C
+			if err := json.Unmarshal(data, &c); err != nil {
+				return nil, err
+			}
+			return newEvent(c), nil
+		},
+		replay: func(s *ecsys.ECS, _ *event.Bus, data []byte) error {
+			var c C
+			if err := json.Unmarshal(data, &c); err != nil {
+				return err
+			}
+			return apply(s, c)
+		},
+	}
+}
+
+// entityCodec returns the codec of an entity event. Replaying the event creates or deletes
+// the entity with the generation it had, which publishes the event again.
+func entityCodec[E any, P interface {
+	*E
+	event.Event
+}](entityOf func(P) entity.Entity, apply func(*ecsys.ECS, entity.Entity) error) codec {
+	c := eventCodec[E, P]()
+	c.replay = func(s *ecsys.ECS, _ *event.Bus, data []byte) error {
+		e, err := c.decode(data)
+		if err != nil {
+			return err
+		}
+		return apply(s, entityOf(e.(P))) //nolint: forcetypeassert // decoded as P
+	}
+	return c
+}
+
+// eventCodec returns the codec of an event that is not tied to the ECS. Replaying the
+// event publishes it on the bus.
+func eventCodec[E any, P interface {
+	*E
+	event.Event
+}]() codec {
+	decode := func(data []byte) (event.Event, error) {
+		var e E
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		return P(&e), nil
+	}
+	return codec{
+		decode: decode,
+		replay: func(_ *ecsys.ECS, bus *event.Bus, data []byte) error {
+			e, err := decode(data)
+			if err != nil {
+				return err
+			}
+			return bus.Publish(e)
+		},
+	}
+}
+
+// remove deletes the component. Deleting the position of an entity also deletes the
+// positions of its descendants, which are recorded as well, so a component that is already
+// deleted by the replay is not an error.
+func remove[C any, T ecsys.ComponentPointer[C]](s *ecsys.ECS, c C) error {
+	if err := ecsys.Delete[C, T](s, c); err != nil && !errors.Is(err, ecsys.ErrComponentNotFound) {
+		return err
+	}
+	return nil
+}
+
+// add adds the component with the ID it had when it was recorded.
+func add[C any, T ecsys.ComponentPointer[C]](s *ecsys.ECS, c C) error {
+	_, err := ecsys.Add[C, T](s, c)
+	return err
+}
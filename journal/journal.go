@@ -0,0 +1,147 @@
+// package journal records the events published on a bus to a file and replays them.
+//
+// A journal holds one JSON record per line with the frame the event was dispatched in,
+// the type of the event and the event itself. Component events hold the component as it
+// was when the event was published. Records are only appended, so a journal can hold
+// several sessions. Every session starts with a session record, written when the world
+// matches the save, and its frames start at 1.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/event"
+)
+
+// ErrUnknownEvent is returned for events of a type the journal cannot serialize.
+var ErrUnknownEvent = errors.New("unknown event type")
+
+// SessionRecordType is the event type of the record that starts a session. It holds no event.
+const SessionRecordType = "journal.session"
+
+// Record is an event in the journal.
+type Record struct {
+	Frame uint64          `json:"frame"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Session reports whether the record starts a session.
+func (r Record) Session() bool { return r.Event == SessionRecordType }
+
+// Decode returns the event of the record.
+func (r Record) Decode() (event.Event, error) {
+	c, ok := codecs[r.Event]
+	if !ok {
+		return nil, fmt.Errorf("could not decode event %s: %w", r.Event, ErrUnknownEvent)
+	}
+	return c.decode(r.Data)
+}
+
+// Open opens the journal at the path for appending, it is created if it does not exist.
+func Open(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+}
+
+// Writer writes events to a journal. Writes are buffered until Flush is called.
+type Writer struct {
+	mu    sync.Mutex
+	buf   *bufio.Writer
+	enc   *json.Encoder
+	frame uint64
+}
+
+// NewWriter creates a writer that appends records to w.
+func NewWriter(w io.Writer) *Writer {
+	buf := bufio.NewWriter(w)
+	return &Writer{
+		buf: buf,
+		enc: json.NewEncoder(buf),
+	}
+}
+
+// SetFrame sets the frame of the events that are written next.
+func (w *Writer) SetFrame(frame uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.frame = frame
+}
+
+// StartSession writes a session record. The records after it are applied to the world as it is
+// when the session starts, so it is written right after the world has been loaded or saved.
+func (w *Writer) StartSession() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.enc.Encode(Record{Frame: w.frame, Event: SessionRecordType}); err != nil {
+		return fmt.Errorf("could not write session record: %w", err)
+	}
+	return nil
+}
+
+// Write writes the event to the journal. It returns ErrUnknownEvent if the event type has no codec.
+func (w *Writer) Write(e event.Event) error {
+	if _, ok := codecs[e.Event()]; !ok {
+		return fmt.Errorf("could not write event %s: %w", e.Event(), ErrUnknownEvent)
+	}
+	var v any = e
+	if ce, ok := e.(component.Event); ok {
+		v = ce.Component()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("could not encode event %s: %w", e.Event(), err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err = w.enc.Encode(Record{Frame: w.frame, Event: e.Event(), Data: data}); err != nil {
+		return fmt.Errorf("could not write event %s: %w", e.Event(), err)
+	}
+	return nil
+}
+
+// Flush writes the buffered records to the underlying writer.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Flush()
+}
+
+// Attach subscribes the writer to every event on the bus. It has the highest priority, so
+// events are recorded before a handler can consume them. Events without a codec are skipped.
+// It returns an identifier for the subscription, which can be used to unsubscribe it later.
+func (w *Writer) Attach(bus *event.Bus) int {
+	return bus.Subscribe(event.MatchAll(), func(e event.Event) error {
+		if err := w.Write(e); err != nil && !errors.Is(err, ErrUnknownEvent) {
+			return err
+		}
+		return nil
+	}, event.WithName("journal"), event.WithPriority(math.MaxInt))
+}
+
+// Reader reads the records of a journal.
+type Reader struct {
+	dec *json.Decoder
+}
+
+// NewReader creates a reader that reads records from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{dec: json.NewDecoder(r)}
+}
+
+// Read returns the next record. It returns io.EOF when there are no more records.
+func (r *Reader) Read() (Record, error) {
+	var rec Record
+	if err := r.dec.Decode(&rec); err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
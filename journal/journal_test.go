@@ -0,0 +1,256 @@
+package journal_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/dwethmar/vork/component/tags"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/event/mouse"
+	"github.com/dwethmar/vork/journal"
+	"github.com/dwethmar/vork/point"
+	"github.com/google/go-cmp/cmp"
+)
+
+// unknownEvent is an event without a codec.
+type unknownEvent struct{}
+
+func (unknownEvent) Event() string { return "unknown" }
+
+// record plays a short session on the ECS and records it to a journal.
+func record(t *testing.T, ecs *ecsys.ECS, bus *event.Bus) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	w := journal.NewWriter(&buf)
+	w.Attach(bus)
+
+	w.SetFrame(1)
+	parent, err := ecs.CreateEntity(ecs.Root(), point.New(10, 10))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	child, err := ecs.CreateEntity(parent, point.New(1, 2))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	v := velocity.New(child, point.New(1, 0))
+	if v.I, err = ecsys.Add(ecs, *v); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err = ecsys.Add(ecs, *tags.New(parent, "a", "b")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	w.SetFrame(2)
+	v.Point = point.New(0, 1)
+	if err = ecsys.Update(ecs, *v); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	for _, e := range []event.Event{mouse.NewLeftClickedEvent(3, 4), unknownEvent{}} {
+		if err = bus.Publish(e); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if err = w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	return &buf
+}
+
+func TestWriter(t *testing.T) {
+	bus := event.NewBus()
+	buf := record(t, ecsys.New(bus, ecsys.NewStores()), bus)
+
+	r := journal.NewReader(buf)
+	var got []string
+	var frames []uint64
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		e, err := rec.Decode()
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if e.Event() != rec.Event {
+			t.Errorf("Decode() event = %s, want %s", e.Event(), rec.Event)
+		}
+		got = append(got, rec.Event)
+		frames = append(frames, rec.Frame)
+	}
+	want := []string{
+		"entity.created", "position.created", "entity.created", "position.created", "velocity.created", "tags.created",
+		"velocity.updated", mouse.LeftMouseClickedEventType,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("recorded events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]uint64{1, 1, 1, 1, 1, 1, 2, 2}, frames); diff != "" {
+		t.Errorf("recorded frames mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	bus := event.NewBus()
+	ecs := ecsys.New(bus, ecsys.NewStores())
+	buf := record(t, ecs, bus)
+
+	replayBus := event.NewBus()
+	replayBus.SetMode(event.Queued)
+	replayed := ecsys.New(replayBus, ecsys.NewStores())
+	var clicks []mouse.LeftClickedEvent
+	event.Subscribe(replayBus, func(e *mouse.LeftClickedEvent) error {
+		clicks = append(clicks, *e)
+		return nil
+	})
+
+	n, err := journal.Replay(buf, replayed, replayBus)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if n != 8 {
+		t.Errorf("Replay() = %d, want 8", n)
+	}
+	if diff := ecsys.Diff(ecs.Snapshot(), replayed.Snapshot()); len(diff.Entities) != 0 {
+		t.Errorf("replayed world differs: %v", diff)
+	}
+	if diff := cmp.Diff([]mouse.LeftClickedEvent{{X: 3, Y: 4}}, clicks); diff != "" {
+		t.Errorf("clicks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplay_ReusedIndex(t *testing.T) {
+	bus := event.NewBus()
+	ecs := ecsys.New(bus, ecsys.NewStores())
+	var buf bytes.Buffer
+	w := journal.NewWriter(&buf)
+	w.Attach(bus)
+
+	w.SetFrame(1)
+	e, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if err = ecs.DeleteEntity(e); err != nil {
+		t.Fatalf("DeleteEntity() error = %v", err)
+	}
+	w.SetFrame(2)
+	reused, err := ecs.CreateEntity(ecs.Root(), point.New(2, 2))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if reused.Index() != e.Index() || reused.Generation() != e.Generation()+1 {
+		t.Fatalf("CreateEntity() = %v, want the index of %v with the next generation", reused, e)
+	}
+	if err = w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	replayBus := event.NewBus()
+	replayed := ecsys.New(replayBus, ecsys.NewStores())
+	if _, err = journal.Replay(&buf, replayed, replayBus); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if replayed.Alive(e) {
+		t.Errorf("Alive(%v) = true, want false", e)
+	}
+	if !replayed.Alive(reused) {
+		t.Errorf("Alive(%v) = false, want true", reused)
+	}
+	if diff := ecsys.Diff(ecs.Snapshot(), replayed.Snapshot()); len(diff.Entities) != 0 {
+		t.Errorf("replayed world differs: %v", diff)
+	}
+}
+
+func TestReplay_DeletedParent(t *testing.T) {
+	bus := event.NewBus()
+	ecs := ecsys.New(bus, ecsys.NewStores())
+	var buf bytes.Buffer
+	w := journal.NewWriter(&buf)
+	w.Attach(bus)
+
+	w.SetFrame(1)
+	parent, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	child, err := ecs.CreateEntity(parent, point.New(2, 2))
+	if err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if _, err = ecs.CreateEntity(child, point.New(3, 3)); err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	w.SetFrame(2)
+	if err = ecs.DeleteEntity(parent); err != nil {
+		t.Fatalf("DeleteEntity() error = %v", err)
+	}
+	if err = w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	replayBus := event.NewBus()
+	replayed := ecsys.New(replayBus, ecsys.NewStores())
+	if _, err = journal.Replay(&buf, replayed, replayBus); err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if replayed.Alive(parent) {
+		t.Errorf("Alive(%v) = true, want false", parent)
+	}
+	if diff := ecsys.Diff(ecs.Snapshot(), replayed.Snapshot()); len(diff.Entities) != 0 {
+		t.Errorf("replayed world differs: %v", diff)
+	}
+}
+
+func TestReplay_LastSession(t *testing.T) {
+	bus := event.NewBus()
+	ecs := ecsys.New(bus, ecsys.NewStores())
+	var buf bytes.Buffer
+	w := journal.NewWriter(&buf)
+	w.Attach(bus)
+
+	// The first session is part of the save the last session starts on.
+	if err := w.StartSession(); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+	w.SetFrame(1)
+	if _, err := ecs.CreateEntity(ecs.Root(), point.New(1, 1)); err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	save := ecs.Snapshot()
+
+	if err := w.StartSession(); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+	w.SetFrame(1)
+	if _, err := ecs.CreateEntity(ecs.Root(), point.New(2, 2)); err != nil {
+		t.Fatalf("CreateEntity() error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	replayBus := event.NewBus()
+	replayed := ecsys.New(replayBus, ecsys.NewStores())
+	if err := replayed.Restore(save); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	n, err := journal.Replay(&buf, replayed, replayBus)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Replay() = %d, want 2", n)
+	}
+	if diff := ecsys.Diff(ecs.Snapshot(), replayed.Snapshot()); len(diff.Entities) != 0 {
+		t.Errorf("replayed world differs: %v", diff)
+	}
+}
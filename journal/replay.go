@@ -0,0 +1,68 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/event"
+)
+
+// Replay reads the journal and feeds the events of its last session back into the ECS and
+// the bus. Entity and component events are applied to the ECS as the change they describe,
+// which publishes them on the bus of the ECS again. Other events are published on the bus.
+// The bus is flushed after every frame, so queued events are dispatched as they were during
+// the session.
+//
+// Entities and components keep the IDs they had in the session, so the ECS should hold the
+// world the session started on, which is the save it was loaded from. A journal without
+// session records is replayed as a whole on an empty world.
+// It returns the number of replayed records.
+func Replay(r io.Reader, ecs *ecsys.ECS, bus *event.Bus) (int, error) {
+	records, err := lastSession(NewReader(r))
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	var frame uint64
+	for _, rec := range records {
+		if rec.Frame != frame {
+			if err = bus.Flush(); err != nil {
+				return n, fmt.Errorf("could not flush frame %d: %w", frame, err)
+			}
+			frame = rec.Frame
+		}
+		c, ok := codecs[rec.Event]
+		if !ok {
+			return n, fmt.Errorf("could not replay record %d: %s: %w", n+1, rec.Event, ErrUnknownEvent)
+		}
+		if err = c.replay(ecs, bus, rec.Data); err != nil {
+			return n, fmt.Errorf("could not replay record %d (%s in frame %d): %w", n+1, rec.Event, rec.Frame, err)
+		}
+		n++
+	}
+	if err = bus.Flush(); err != nil {
+		return n, fmt.Errorf("could not flush frame %d: %w", frame, err)
+	}
+	return n, nil
+}
+
+// lastSession reads the records of the last session in the journal.
+func lastSession(r *Reader) ([]Record, error) {
+	var records []Record
+	for i := 1; ; i++ {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read record %d: %w", i, err)
+		}
+		if rec.Session() {
+			records = records[:0]
+			continue
+		}
+		records = append(records, rec)
+	}
+}
@@ -0,0 +1,61 @@
+package bbolt
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+
+	"github.com/dwethmar/vork/resource"
+	bolt "go.etcd.io/bbolt"
+)
+
+// resourceBucket is the bucket in which the resources are saved, keyed by their type.
+var resourceBucket = []byte("resources")
+
+// SaveResource saves a resource, encoded using gob, under its type.
+func SaveResource(tx *bolt.Tx, r resource.Resource) error {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode resource: %w", err)
+	}
+
+	bucket, err := tx.CreateBucketIfNotExists(resourceBucket)
+	if err != nil {
+		return fmt.Errorf("failed to create or get bucket: %w", err)
+	}
+
+	if err = bucket.Put([]byte(r.Type()), buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to save resource: %w", err)
+	}
+
+	return nil
+}
+
+// LoadResource loads the saved resource of the same type as r and decodes it into a new value
+// of the Go type of r. It returns false if no resource of that type is saved.
+func LoadResource(tx *bolt.Tx, r resource.Resource) (resource.Resource, bool, error) {
+	bucket := tx.Bucket(resourceBucket)
+	if bucket == nil {
+		return nil, false, nil
+	}
+
+	v := bucket.Get([]byte(r.Type()))
+	if v == nil {
+		return nil, false, nil
+	}
+
+	// Decode into a new value of the same Go type as r
+	ptr := reflect.New(reflect.TypeOf(r))
+	dec := gob.NewDecoder(bytes.NewBuffer(v))
+	if err := dec.DecodeValue(ptr); err != nil {
+		return nil, false, fmt.Errorf("failed to decode resource: %w", err)
+	}
+
+	loaded, ok := ptr.Elem().Interface().(resource.Resource)
+	if !ok {
+		return nil, false, fmt.Errorf("decoded %T is not a resource", ptr.Elem().Interface())
+	}
+	return loaded, true, nil
+}
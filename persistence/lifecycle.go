@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/dwethmar/vork/component"
 	"github.com/dwethmar/vork/ecsys"
@@ -15,47 +16,47 @@ type ComponentLifeCycle interface {
 }
 
 type GenericComponentLifeCycle[T component.Component] struct {
-	repo                Repository[T]
-	changed             map[uint]T
-	deleted             map[uint]T
-	componentMarkerFunc func(component.Component, map[uint]T) error // Function to mark the component as changed
-	store               ecsys.Store[T]
+	mu      sync.Mutex // Guards changed and deleted, components may change on several goroutines.
+	repo    Repository[T]
+	changed map[uint]T
+	deleted map[uint]T
+	stores  *ecsys.Stores
 }
 
-func NewGenericComponentLifeCycle[T component.Component](
-	repo Repository[T],
-	store ecsys.Store[T],
-	componentMarkerFunc func(component.Component, map[uint]T) error,
-) *GenericComponentLifeCycle[T] {
+func NewGenericComponentLifeCycle[T component.Component](repo Repository[T], stores *ecsys.Stores) *GenericComponentLifeCycle[T] {
 	return &GenericComponentLifeCycle[T]{
-		repo:                repo,
-		changed:             make(map[uint]T),
-		deleted:             make(map[uint]T),
-		componentMarkerFunc: componentMarkerFunc,
-		store:               store,
+		repo:    repo,
+		changed: make(map[uint]T),
+		deleted: make(map[uint]T),
+		stores:  stores,
 	}
 }
 
 // Changed is called when a component has changed.
 func (l *GenericComponentLifeCycle[T]) Changed(e component.Component, deleted bool) error {
+	c, ok := e.(T)
+	if !ok {
+		var zero T
+		return fmt.Errorf("expected %T, got %T", zero, e)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if deleted {
 		delete(l.changed, e.ID())
-		if err := l.componentMarkerFunc(e, l.deleted); err != nil {
-			return fmt.Errorf("could not mark component as deleted: %w", err)
-		}
+		l.deleted[e.ID()] = c
 	} else {
 		if _, ok := l.deleted[e.ID()]; ok {
 			return fmt.Errorf("component %d is already deleted", e.ID())
 		}
-		if err := l.componentMarkerFunc(e, l.changed); err != nil {
-			return fmt.Errorf("could not mark component as changed: %w", err)
-		}
+		l.changed[e.ID()] = c
 	}
 	return nil
 }
 
 // Commit saves all changes to the database.
 func (l *GenericComponentLifeCycle[T]) Commit(tx *bolt.Tx) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	for _, p := range l.changed {
 		if err := l.repo.Save(tx, p); err != nil {
 			return err
@@ -77,7 +78,7 @@ func (l *GenericComponentLifeCycle[T]) Load(tx *bolt.Tx) error {
 		return err
 	}
 	for _, c := range components {
-		if _, err = l.store.Add(c); err != nil {
+		if err = l.stores.Insert(c); err != nil {
 			return err
 		}
 	}
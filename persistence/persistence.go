@@ -3,19 +3,20 @@ package persistence
 import (
 	"fmt"
 	"log/slog"
-	"slices"
+	"sync"
 
 	"github.com/dwethmar/vork/component"
-	"github.com/dwethmar/vork/component/controllable"
-	"github.com/dwethmar/vork/component/position"
-	"github.com/dwethmar/vork/component/skeleton"
-	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/event"
 	boltrepo "github.com/dwethmar/vork/persistence/bbolt"
+	"github.com/dwethmar/vork/resource"
 	bolt "go.etcd.io/bbolt"
 )
 
+// EventPriority is the priority of the handler that records changes. It is higher than
+// the default, so changes are recorded before systems react to them.
+const EventPriority = 100
+
 // Persistance saves and loads components from the database.
 type Persistance struct {
 	logger     *slog.Logger
@@ -23,6 +24,8 @@ type Persistance struct {
 	ecs        *ecsys.ECS
 	lifecycles map[component.Type]ComponentLifeCycle
 	stores     *ecsys.Stores
+	mu         sync.Mutex                          // Guards resources.
+	resources  map[resource.Type]resource.Resource // Persistent resources changed since the last save.
 }
 
 // Options is the configuration for the persistence system.
@@ -37,79 +40,51 @@ type Options struct {
 	ECS *ecsys.ECS
 }
 
-// New creates a new persistence system.
+// New creates a new persistence system. It saves and loads the components of the types
+// that are registered as persistent with the ECS.
 func New(opts Options) *Persistance {
-	stores := opts.Stores
 	s := &Persistance{
-		logger:   opts.Logger.With("system", "persistence"),
-		eventBus: opts.EventBus,
-		ecs:      opts.ECS,
-		stores:   opts.Stores,
-		lifecycles: map[component.Type]ComponentLifeCycle{
-			controllable.Type: NewGenericComponentLifeCycle(
-				boltrepo.NewRepository(controllable.Empty),
-				stores.Controllable,
-				func(c component.Component, m map[uint]*controllable.Controllable) error {
-					cc, ok := c.(*controllable.Controllable)
-					if !ok {
-						return fmt.Errorf("expected *controllable.Controllable, got %T", c)
-					}
-					m[c.ID()] = cc
-					return nil
-				},
-			),
-			position.Type: NewGenericComponentLifeCycle(
-				boltrepo.NewRepository(position.Empty),
-				stores.Position,
-				func(c component.Component, m map[uint]*position.Position) error {
-					cc, ok := c.(*position.Position)
-					if !ok {
-						return fmt.Errorf("expected *position.Position, got %T", c)
-					}
-					m[c.ID()] = cc
-					return nil
-				},
-			),
-			velocity.Type: NewGenericComponentLifeCycle(
-				boltrepo.NewRepository(velocity.Empty),
-				stores.Velocity,
-				func(c component.Component, m map[uint]*velocity.Velocity) error {
-					cc, ok := c.(*velocity.Velocity)
-					if !ok {
-						return fmt.Errorf("expected *velocity.Velocity, got %T", c)
-					}
-					m[c.ID()] = cc
-					return nil
-				},
-			),
-			skeleton.Type: NewGenericComponentLifeCycle(
-				boltrepo.NewRepository(skeleton.Empty),
-				stores.Skeleton,
-				func(e component.Component, m map[uint]*skeleton.Skeleton) error {
-					cc, ok := e.(*skeleton.Skeleton)
-					if !ok {
-						return fmt.Errorf("expected *skeleton.Skeleton, got %T", e)
-					}
-					m[e.ID()] = cc
-					return nil
-				},
-			),
-		},
+		logger:     opts.Logger.With("system", "persistence"),
+		eventBus:   opts.EventBus,
+		ecs:        opts.ECS,
+		stores:     opts.Stores,
+		resources:  make(map[resource.Type]resource.Resource),
+		lifecycles: make(map[component.Type]ComponentLifeCycle),
 	}
 
-	persistentComponentTypes := PersistentComponentTypes()
+	persistentComponentTypes := ecsys.PersistentTypes()
+	for _, t := range persistentComponentTypes {
+		factory, err := ecsys.ComponentFactory(t)
+		if err != nil {
+			s.logger.Error("could not create lifecycle", "component", t, "error", err)
+			continue
+		}
+		s.lifecycles[t] = NewGenericComponentLifeCycle(boltrepo.NewRepository(factory), opts.Stores)
+	}
 
-	// subscribe to component change events for all persistent components.
-	s.eventBus.Subscribe(event.MatcherFunc(func(e event.Event) bool {
-		c, ok := e.(component.Event)
-		return ok && slices.Contains(persistentComponentTypes, c.ComponentType())
-	}), s.componentChangeHandler)
+	// subscribe to component change events for all persistent components
+	// and to change events of resources that are marked as persistent.
+	s.eventBus.Subscribe(event.Or(
+		component.MatchType(persistentComponentTypes...),
+		event.MatcherFunc(func(e event.Event) bool {
+			re, ok := e.(resource.Event)
+			return ok && re.Persistent()
+		}),
+	), s.changeHandler, event.WithName("persistence"), event.WithPriority(EventPriority))
 
 	s.logger.Info("persistence system created", "persistent_components", persistentComponentTypes)
 
 	return s
 }
 
+// changeHandler is called when a component or a resource has changed.
+func (s *Persistance) changeHandler(e event.Event) error {
+	if re, ok := e.(resource.Event); ok {
+		return s.resourceChangeHandler(re)
+	}
+	return s.componentChangeHandler(e)
+}
+
 // componentChangeHandler is called when a component has changed or has been deleted.
 func (s *Persistance) componentChangeHandler(e event.Event) error {
 	ce, ok := e.(component.Event)
@@ -121,54 +96,85 @@ func (s *Persistance) componentChangeHandler(e event.Event) error {
 		return fmt.Errorf("no lifecycle for component type: %s", ce.ComponentType())
 	}
 
-	switch c := ce.(type) {
-	case position.Event:
-		if err := l.Changed(c.Position(), c.Deleted()); err != nil {
-			return fmt.Errorf("failed to mark position component as changed: %w", err)
-		}
-	case velocity.Event:
-		if err := l.Changed(c.Velocity(), c.Deleted()); err != nil {
-			return fmt.Errorf("failed to mark velocity component as changed: %w", err)
-		}
-	case controllable.Event:
-		if err := l.Changed(c.Controllable(), c.Deleted()); err != nil {
-			return fmt.Errorf("failed to mark controllable component as changed: %w", err)
-		}
-	case skeleton.Event:
-		if err := l.Changed(c.Skeleton(), c.Deleted()); err != nil {
-			return fmt.Errorf("failed to mark skeleton component as changed: %w", err)
-		}
-	default:
-		return fmt.Errorf("no handler for component type: %T", c)
+	if err := l.Changed(ce.Component(), ce.Deleted()); err != nil {
+		return fmt.Errorf("failed to mark %s component as changed: %w", ce.ComponentType(), err)
 	}
 	return nil
 }
 
-// Save saves all changed or deleted components to the database.
+// resourceChangeHandler is called when a persistent resource has changed.
+func (s *Persistance) resourceChangeHandler(re resource.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[re.ResourceType()] = re.Resource()
+	return nil
+}
+
+// Save saves all changed or deleted components and all changed persistent resources to the database.
 func (s *Persistance) Save(db *bolt.DB) error {
-	return db.Update(func(tx *bolt.Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := db.Update(func(tx *bolt.Tx) error {
 		for n, l := range s.lifecycles {
 			if err := l.Commit(tx); err != nil {
 				return fmt.Errorf("failed to commit changes for component type %s: %w", n, err)
 			}
 		}
+		for t, r := range s.resources {
+			if err := boltrepo.SaveResource(tx, r); err != nil {
+				return fmt.Errorf("failed to save resource %s: %w", t, err)
+			}
+		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	clear(s.resources)
+	return nil
 }
 
 // Load loads all components from the database and adds them to the ECS.
+// The entities of the loaded components, including their generation, are marked as alive in the ECS.
+// The persistent resources in the ECS are replaced by their saved values, if any.
 func (s *Persistance) Load(db *bolt.DB) error {
-	return db.View(func(tx *bolt.Tx) error {
-		for _, r := range PersistentComponentTypes() {
+	var loaded []resource.Resource
+	err := db.View(func(tx *bolt.Tx) error {
+		for _, r := range ecsys.PersistentTypes() {
 			l, ok := s.lifecycles[r]
 			if !ok {
 				return fmt.Errorf("no lifecycle for component type: %s", r)
 			}
 			if err := l.Load(tx); err != nil {
-				return fmt.Errorf("failed to load controllable components: %w", err)
+				return fmt.Errorf("failed to load %s components: %w", r, err)
+			}
+		}
+		for _, r := range s.ecs.PersistentResources() {
+			l, ok, err := boltrepo.LoadResource(tx, r)
+			if err != nil {
+				return fmt.Errorf("failed to load %s resource: %w", r.Type(), err)
+			}
+			if ok {
+				loaded = append(loaded, l)
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	// insert the loaded resources once the read transaction is closed
+	for _, r := range loaded {
+		if err = s.ecs.InsertResource(r); err != nil {
+			return fmt.Errorf("failed to insert %s resource: %w", r.Type(), err)
+		}
+	}
+	if err = s.ecs.SyncEntities(); err != nil {
+		return fmt.Errorf("failed to sync entities: %w", err)
+	}
+	if err = s.ecs.BuildIndexes(); err != nil {
+		return fmt.Errorf("failed to build indexes: %w", err)
+	}
+	return nil
 }
@@ -1,17 +1,23 @@
 package persistence_test
 
 import (
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
 
 	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/name"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/relation"
 	"github.com/dwethmar/vork/component/skeleton"
+	"github.com/dwethmar/vork/component/tags"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
 	"github.com/dwethmar/vork/persistence"
 	"github.com/dwethmar/vork/point"
+	"github.com/dwethmar/vork/resource"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -105,13 +111,13 @@ func TestSystem_Save(t *testing.T) { //nolint: gocognit
 
 			// Add controllable component
 			ctrl := controllable.New(e)
-			if _, err = ecs.AddControllable(*ctrl); err != nil {
+			if _, err = ecsys.Add(ecs, *ctrl); err != nil {
 				t.Fatalf("Failed to add controllable component: %v", err)
 			}
 
 			// Add skeleton component
 			skel := skeleton.New(e)
-			if _, err = ecs.AddSkeleton(*skel); err != nil {
+			if _, err = ecsys.Add(ecs, *skel); err != nil {
 				t.Fatalf("Failed to add skeleton component: %v", err)
 			}
 		}
@@ -146,7 +152,7 @@ func TestSystem_Save(t *testing.T) { //nolint: gocognit
 			}
 
 			// Verify position component
-			pos, err := ecs.GetPosition(e)
+			pos, err := ecsys.Get[position.Position](ecs, e)
 			if err != nil {
 				t.Fatalf("Failed to get position component: %v", err)
 			}
@@ -155,12 +161,12 @@ func TestSystem_Save(t *testing.T) { //nolint: gocognit
 			}
 
 			// Verify controllable component
-			if _, err = ecs.GetControllable(e); err != nil {
+			if _, err = ecsys.Get[controllable.Controllable](ecs, e); err != nil {
 				t.Fatalf("Failed to get controllable component: %v", err)
 			}
 
 			// Verify skeleton component
-			if _, err = ecs.GetSkeleton(e); err != nil {
+			if _, err = ecsys.Get[skeleton.Skeleton](ecs, e); err != nil {
 				t.Fatalf("Failed to get skeleton component: %v", err)
 			}
 		}
@@ -195,8 +201,8 @@ func TestSystem_Save(t *testing.T) { //nolint: gocognit
 		}
 
 		// Delete position component of entity 50
-		if con, err := ecs.GetPosition(entity.Entity(50)); err == nil {
-			if err = ecs.DeletePosition(con); err != nil {
+		if con, err := ecsys.Get[position.Position](ecs, entity.Entity(50)); err == nil {
+			if err = ecsys.Delete(ecs, con); err != nil {
 				t.Fatalf("Failed to delete position component: %v", err)
 			}
 		} else {
@@ -225,7 +231,7 @@ func TestSystem_Save(t *testing.T) { //nolint: gocognit
 		// Verify components
 		for i := 1; i <= 100; i++ {
 			e := entity.Entity(i)
-			pos, err := ecs.GetPosition(e)
+			pos, err := ecsys.Get[position.Position](ecs, e)
 			if i == 50 {
 				if err == nil {
 					t.Errorf("expected position component for entity %d to be deleted", e)
@@ -271,7 +277,7 @@ func TestSystem_Load(t *testing.T) {
 				t.Errorf("CreateEntity failed: %v", err)
 			}
 
-			pos, err := ecs.GetPosition(e)
+			pos, err := ecsys.Get[position.Position](ecs, e)
 			if err != nil {
 				t.Errorf("Position failed: %v", err)
 			}
@@ -279,8 +285,8 @@ func TestSystem_Load(t *testing.T) {
 			// update position
 			pos.SetCords(33, 44)
 
-			if err = ecs.UpdatePositionComponent(pos); err != nil {
-				t.Errorf("UpdatePosition failed: %v", err)
+			if err = ecsys.Update(ecs, pos); err != nil {
+				t.Errorf("Update failed: %v", err)
 			}
 
 			if err = s.Save(db); err != nil {
@@ -304,7 +310,7 @@ func TestSystem_Load(t *testing.T) {
 			}
 
 			// check ecs for loaded components
-			position, err := ecs.GetPosition(e)
+			position, err := ecsys.Get[position.Position](ecs, e)
 			if err != nil {
 				t.Errorf("Position failed: %v", err)
 			}
@@ -314,3 +320,262 @@ func TestSystem_Load(t *testing.T) {
 		}
 	})
 }
+
+func TestSystem_LoadResources(t *testing.T) {
+	t.Run("Load should load the persistent resources", func(t *testing.T) {
+		path := t.TempDir() + "/test.db"
+		db := openTestDB(t, path)
+		t.Cleanup(func() {
+			closeTestDB(t, db, path)
+		})
+
+		newECS := func() (*ecsys.ECS, *persistence.Persistance) {
+			eventBus := event.NewBus()
+			stores := ecsys.NewStores()
+			ecs := ecsys.New(eventBus, stores)
+			s := persistence.New(persistence.Options{
+				Logger:   slog.Default(),
+				EventBus: eventBus,
+				Stores:   stores,
+				ECS:      ecs,
+			})
+			if err := ecs.InsertResource(resource.NewCamera(), ecsys.Persistent()); err != nil {
+				t.Fatalf("InsertResource failed: %v", err)
+			}
+			if err := ecs.InsertResource(resource.Time{}); err != nil {
+				t.Fatalf("InsertResource failed: %v", err)
+			}
+			return ecs, s
+		}
+
+		{
+			ecs, s := newECS()
+			if err := ecs.InsertResource(resource.Camera{X: 1, Y: 2, Zoom: 3}); err != nil {
+				t.Fatalf("InsertResource failed: %v", err)
+			}
+			if err := ecsys.UpdateResource(ecs, func(t *resource.Time) bool { t.Frame = 10; return true }); err != nil {
+				t.Fatalf("UpdateResource failed: %v", err)
+			}
+			if err := s.Save(db); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+
+		{
+			ecs, s := newECS()
+			if err := s.Load(db); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			cam, err := ecsys.GetResource[resource.Camera](ecs)
+			if err != nil {
+				t.Fatalf("Camera failed: %v", err)
+			}
+			if want := (resource.Camera{X: 1, Y: 2, Zoom: 3}); cam != want {
+				t.Errorf("Camera = %+v, want %+v", cam, want)
+			}
+			tm, err := ecsys.GetResource[resource.Time](ecs)
+			if err != nil {
+				t.Fatalf("Time failed: %v", err)
+			}
+			if tm.Frame != 0 {
+				t.Errorf("Time.Frame = %d, want 0, time is not persistent", tm.Frame)
+			}
+		}
+	})
+}
+
+func TestSystem_LoadIndexes(t *testing.T) {
+	t.Run("Load should index the loaded names, tags and relations", func(t *testing.T) {
+		path := t.TempDir() + "/test.db"
+		db := openTestDB(t, path)
+		t.Cleanup(func() {
+			closeTestDB(t, db, path)
+		})
+
+		var e, owner entity.Entity
+		{
+			eventBus := event.NewBus()
+			stores := ecsys.NewStores()
+			ecs := ecsys.New(eventBus, stores)
+			s := persistence.New(persistence.Options{
+				Logger:   slog.Default(),
+				EventBus: eventBus,
+				Stores:   stores,
+				ECS:      ecs,
+			})
+			var err error
+			if e, err = ecs.CreateEntity(ecs.Root(), point.Zero()); err != nil {
+				t.Fatalf("CreateEntity failed: %v", err)
+			}
+			if owner, err = ecs.CreateEntity(ecs.Root(), point.Zero()); err != nil {
+				t.Fatalf("CreateEntity failed: %v", err)
+			}
+			if err = ecs.Relate(relation.OwnedBy, e, owner); err != nil {
+				t.Fatalf("Relate failed: %v", err)
+			}
+			if _, err = ecsys.Add(ecs, *name.New(e, "player")); err != nil {
+				t.Fatalf("Add name failed: %v", err)
+			}
+			if _, err = ecsys.Add(ecs, *tags.New(e, "hero")); err != nil {
+				t.Fatalf("Add tags failed: %v", err)
+			}
+			if err = s.Save(db); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+
+		{
+			eventBus := event.NewBus()
+			stores := ecsys.NewStores()
+			ecs := ecsys.New(eventBus, stores)
+			s := persistence.New(persistence.Options{
+				Logger:   slog.Default(),
+				EventBus: eventBus,
+				Stores:   stores,
+				ECS:      ecs,
+			})
+			if err := s.Load(db); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			got, err := ecsys.FindEntity[name.Name](ecs, name.Index, "player")
+			if err != nil {
+				t.Fatalf("FindEntity failed: %v", err)
+			}
+			if got != e {
+				t.Errorf("FindEntity = %v, want %v", got, e)
+			}
+			if tagged := ecsys.FindEntities[tags.Tags](ecs, tags.Index, "hero"); len(tagged) != 1 || tagged[0] != e {
+				t.Errorf("FindEntities = %v, want [%v]", tagged, e)
+			}
+			if owned := ecs.RelatedTo(owner, relation.OwnedBy); len(owned) != 1 || owned[0] != e {
+				t.Errorf("RelatedTo = %v, want [%v]", owned, e)
+			}
+		}
+	})
+}
+
+func TestSystem_LoadActive(t *testing.T) {
+	t.Run("Load should restore inactive entities and their subtrees", func(t *testing.T) {
+		path := t.TempDir() + "/test.db"
+		db := openTestDB(t, path)
+		t.Cleanup(func() {
+			closeTestDB(t, db, path)
+		})
+
+		var parent, child entity.Entity
+		{
+			eventBus := event.NewBus()
+			stores := ecsys.NewStores()
+			ecs := ecsys.New(eventBus, stores)
+			s := persistence.New(persistence.Options{
+				Logger:   slog.Default(),
+				EventBus: eventBus,
+				Stores:   stores,
+				ECS:      ecs,
+			})
+			var err error
+			if parent, err = ecs.CreateEntity(ecs.Root(), point.Zero()); err != nil {
+				t.Fatalf("CreateEntity failed: %v", err)
+			}
+			if child, err = ecs.CreateEntity(parent, point.Zero()); err != nil {
+				t.Fatalf("CreateEntity failed: %v", err)
+			}
+			if err = ecs.SetActive(parent, false); err != nil {
+				t.Fatalf("SetActive failed: %v", err)
+			}
+			if err = s.Save(db); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+
+		eventBus := event.NewBus()
+		stores := ecsys.NewStores()
+		ecs := ecsys.New(eventBus, stores)
+		s := persistence.New(persistence.Options{
+			Logger:   slog.Default(),
+			EventBus: eventBus,
+			Stores:   stores,
+			ECS:      ecs,
+		})
+		if err := s.Load(db); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if err := ecs.BuildHierarchy(); err != nil {
+			t.Fatalf("BuildHierarchy failed: %v", err)
+		}
+		for _, e := range []entity.Entity{parent, child} {
+			if ecs.Active(e) {
+				t.Errorf("expected entity %v to be inactive", e)
+			}
+		}
+		if !ecs.ActiveSelf(child) {
+			t.Errorf("expected entity %v to be active itself", child)
+		}
+	})
+}
+
+func TestSystem_LoadGeneration(t *testing.T) {
+	t.Run("Load should restore the generation of entities", func(t *testing.T) {
+		path := t.TempDir() + "/test.db"
+		db := openTestDB(t, path)
+		t.Cleanup(func() {
+			closeTestDB(t, db, path)
+		})
+
+		var stale, e entity.Entity
+		{
+			eventBus := event.NewBus()
+			stores := ecsys.NewStores()
+			ecs := ecsys.New(eventBus, stores)
+			s := persistence.New(persistence.Options{
+				Logger:   slog.Default(),
+				EventBus: eventBus,
+				Stores:   stores,
+				ECS:      ecs,
+			})
+
+			var err error
+			if stale, err = ecs.CreateEntity(ecs.Root(), point.New(1, 1)); err != nil {
+				t.Fatalf("CreateEntity failed: %v", err)
+			}
+			if err = ecs.DeleteEntity(stale); err != nil {
+				t.Fatalf("DeleteEntity failed: %v", err)
+			}
+			if e, err = ecs.CreateEntity(ecs.Root(), point.New(2, 2)); err != nil {
+				t.Fatalf("CreateEntity failed: %v", err)
+			}
+			if e.Generation() == 0 {
+				t.Fatalf("expected entity %v to have a generation", e)
+			}
+			if err = s.Save(db); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+
+		eventBus := event.NewBus()
+		stores := ecsys.NewStores()
+		ecs := ecsys.New(eventBus, stores)
+		s := persistence.New(persistence.Options{
+			Logger:   slog.Default(),
+			EventBus: eventBus,
+			Stores:   stores,
+			ECS:      ecs,
+		})
+		if err := s.Load(db); err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+
+		if !ecs.Alive(e) {
+			t.Errorf("expected entity %v to be alive", e)
+		}
+		if ecs.Alive(stale) {
+			t.Errorf("expected entity %v to be stale", stale)
+		}
+		if _, err := ecsys.Get[position.Position](ecs, stale); !errors.Is(err, ecsys.ErrEntityNotFound) {
+			t.Errorf("expected ErrEntityNotFound, got %v", err)
+		}
+		if pos, err := ecsys.Get[position.Position](ecs, e); err != nil || pos.Point != point.New(2, 2) {
+			t.Errorf("Get position failed: %v, %v", pos, err)
+		}
+	})
+}
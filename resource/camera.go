@@ -0,0 +1,26 @@
+package resource
+
+const CameraType = Type("camera")
+
+// Camera is the part of the world that is drawn on the screen.
+type Camera struct {
+	X, Y int     // Top left of the screen in world coordinates.
+	Zoom float64 // Zoom factor, 1 draws the world at its original size.
+}
+
+// NewCamera creates a new camera at the origin without zoom.
+func NewCamera() Camera {
+	return Camera{Zoom: 1}
+}
+
+func (Camera) Type() Type { return CameraType }
+
+// ScreenToWorld converts a position on the screen to a position in the world.
+func (c Camera) ScreenToWorld(x, y int) (int, int) {
+	return int(float64(x)/c.Zoom) + c.X, int(float64(y)/c.Zoom) + c.Y
+}
+
+// WorldToScreen converts a position in the world to a position on the screen.
+func (c Camera) WorldToScreen(x, y float64) (float64, float64) {
+	return (x - float64(c.X)) * c.Zoom, (y - float64(c.Y)) * c.Zoom
+}
@@ -0,0 +1,34 @@
+package resource
+
+import "github.com/dwethmar/vork/event"
+
+var _ Event = &ChangedEvent{}
+
+// ChangedEventType returns the event type for when a resource of type t is changed.
+func ChangedEventType(t Type) string {
+	return "resource." + string(t) + ".changed"
+}
+
+// Event is a change in a resource.
+type Event interface {
+	event.Event
+	Resource() Resource
+	ResourceType() Type
+	Persistent() bool
+}
+
+type ChangedEvent struct {
+	resource   Resource
+	persistent bool
+}
+
+// NewChangedEvent creates a new event for a changed resource.
+// Persistent reports whether the resource is saved by the persistence system.
+func NewChangedEvent(r Resource, persistent bool) *ChangedEvent {
+	return &ChangedEvent{resource: r, persistent: persistent}
+}
+
+func (e *ChangedEvent) Event() string      { return ChangedEventType(e.resource.Type()) }
+func (e *ChangedEvent) Resource() Resource { return e.resource }
+func (e *ChangedEvent) ResourceType() Type { return e.resource.Type() }
+func (e *ChangedEvent) Persistent() bool   { return e.persistent }
@@ -0,0 +1,13 @@
+package resource
+
+const InputStateType = Type("input")
+
+// InputState is the state of the input devices during the current frame.
+type InputState struct {
+	DirectionX, DirectionY int  // Direction keys that are pressed, -1, 0 or 1 per axis.
+	CursorX, CursorY       int  // Position of the cursor on the screen.
+	LeftClicked            bool // Left mouse button was pressed this frame.
+	WheelY                 float64
+}
+
+func (InputState) Type() Type { return InputStateType }
@@ -0,0 +1,10 @@
+// Package resource contains the global resources of the game. Unlike components,
+// resources are not associated with an entity: the ECS holds a single value per resource type.
+package resource
+
+type Type string
+
+// Resource is an interface that all resources must implement.
+type Resource interface {
+	Type() Type
+}
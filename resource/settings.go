@@ -0,0 +1,12 @@
+package resource
+
+const GameSettingsType = Type("settings")
+
+// GameSettings are the settings of a saved game.
+type GameSettings struct {
+	SaveName            string
+	VelocityScaleFactor int // Scale factor for the velocity
+	Friction            int // Friction to apply to the velocity
+}
+
+func (GameSettings) Type() Type { return GameSettingsType }
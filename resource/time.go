@@ -0,0 +1,21 @@
+package resource
+
+import "time"
+
+const TimeType = Type("time")
+
+// Time is the game time.
+type Time struct {
+	Frame   uint64        // Number of frames since the game started.
+	Delta   time.Duration // Duration of the last frame.
+	Elapsed time.Duration // Game time since the game started.
+}
+
+func (Time) Type() Type { return TimeType }
+
+// Advance advances the time by one frame of duration delta.
+func (t *Time) Advance(delta time.Duration) {
+	t.Frame++
+	t.Delta = delta
+	t.Elapsed += delta
+}
@@ -0,0 +1,109 @@
+// package scheduler runs systems concurrently based on the component types they access.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dwethmar/vork/ecsys"
+)
+
+// ErrDependencyFailed is returned for a system that did not run because a system it depends on failed.
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// System is a system that can be scheduled.
+type System interface {
+	Update() error
+}
+
+// AccessDeclarer is implemented by systems that declare the component types they access.
+// A system that does not implement it is assumed to access everything and never runs
+// concurrently with another system.
+type AccessDeclarer interface {
+	Access() ecsys.Access
+}
+
+// Scheduler runs the updates of systems concurrently. A system depends on every system
+// before it whose access conflicts with its own and only starts when those are done, so
+// the result is the same as running the systems one after another in order.
+type Scheduler struct {
+	systems []System
+	deps    [][]int // indices of the systems a system depends on
+}
+
+// New creates a scheduler and builds the dependency graph of the systems.
+// The order of the systems decides the order of systems with conflicting access.
+func New(systems ...System) *Scheduler {
+	access := make([]*ecsys.Access, len(systems))
+	for i, sys := range systems {
+		if d, ok := sys.(AccessDeclarer); ok {
+			a := d.Access()
+			access[i] = &a
+		}
+	}
+	deps := make([][]int, len(systems))
+	for i := range systems {
+		for j := range i {
+			if conflicts(access[i], access[j]) {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	return &Scheduler{
+		systems: systems,
+		deps:    deps,
+	}
+}
+
+// conflicts reports whether two accesses conflict, a nil access conflicts with everything.
+func conflicts(a, b *ecsys.Access) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	return a.Conflicts(*b)
+}
+
+// Dependencies returns the indices of the systems the i-th system waits for.
+func (s *Scheduler) Dependencies(i int) []int {
+	return s.deps[i]
+}
+
+// Update runs the updates of all systems, each on its own goroutine as soon as the systems
+// it depends on are done. Systems that depend on a failed system are not run.
+// It returns the errors of all failed systems.
+func (s *Scheduler) Update() error {
+	done := make([]chan struct{}, len(s.systems))
+	errs := make([]error, len(s.systems))
+	for i := range s.systems {
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i, sys := range s.systems {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			for _, d := range s.deps[i] {
+				<-done[d]
+				if errs[d] != nil {
+					errs[i] = fmt.Errorf("system %T not run: %w", sys, ErrDependencyFailed)
+					return
+				}
+			}
+			if err := sys.Update(); err != nil {
+				errs[i] = fmt.Errorf("failed to update system %T: %w", sys, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, ErrDependencyFailed) {
+			failed = append(failed, err)
+		}
+	}
+	return errors.Join(failed...)
+}
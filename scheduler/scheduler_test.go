@@ -0,0 +1,249 @@
+package scheduler_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/sprite"
+	"github.com/dwethmar/vork/component/velocity"
+	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
+	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
+	"github.com/dwethmar/vork/resource"
+	"github.com/dwethmar/vork/scheduler"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testSystem is a system that declares its access and calls update.
+type testSystem struct {
+	access ecsys.Access
+	update func() error
+}
+
+func (s *testSystem) Access() ecsys.Access { return s.access }
+func (s *testSystem) Update() error        { return s.update() }
+
+// exclusiveSystem does not declare its access.
+type exclusiveSystem struct {
+	update func() error
+}
+
+func (s *exclusiveSystem) Update() error { return s.update() }
+
+func reads(t ...component.Type) ecsys.Access  { return ecsys.Access{Reads: t} }
+func writes(t ...component.Type) ecsys.Access { return ecsys.Access{Writes: t} }
+
+func TestNew(t *testing.T) {
+	t.Run("should make systems depend on earlier systems with conflicting access", func(t *testing.T) {
+		noop := func() error { return nil }
+		s := scheduler.New(
+			&testSystem{access: writes(velocity.Type), update: noop},                   // 0
+			&testSystem{access: reads(position.Type), update: noop},                    // 1
+			&testSystem{access: reads(velocity.Type), update: noop},                    // 2
+			&testSystem{access: writes(position.Type, sprite.Type), update: noop},      // 3
+			&testSystem{access: reads(hitbox.Type), update: noop},                      // 4
+			&exclusiveSystem{update: noop},                                             // 5
+			&testSystem{access: ecsys.Access{Reads: []component.Type{}}, update: noop}, // 6
+		)
+		want := [][]int{nil, nil, {0}, {1}, nil, {0, 1, 2, 3, 4}, {5}}
+		for i, w := range want {
+			if diff := cmp.Diff(w, s.Dependencies(i)); diff != "" {
+				t.Errorf("Dependencies(%d) mismatch (-want +got):\n%s", i, diff)
+			}
+		}
+	})
+}
+
+func TestNew_Resources(t *testing.T) {
+	t.Run("should make systems depend on earlier systems with conflicting resource access", func(t *testing.T) {
+		noop := func() error { return nil }
+		s := scheduler.New(
+			&testSystem{access: ecsys.Access{WriteResources: []resource.Type{resource.InputStateType}}, update: noop},
+			&testSystem{access: ecsys.Access{ReadResources: []resource.Type{resource.GameSettingsType}}, update: noop},
+			&testSystem{access: ecsys.Access{ReadResources: []resource.Type{resource.InputStateType}}, update: noop},
+		)
+		want := [][]int{nil, nil, {0}}
+		for i, w := range want {
+			if diff := cmp.Diff(w, s.Dependencies(i)); diff != "" {
+				t.Errorf("Dependencies(%d) mismatch (-want +got):\n%s", i, diff)
+			}
+		}
+	})
+}
+
+func TestScheduler_Update(t *testing.T) {
+	t.Run("should run systems without conflicts concurrently", func(t *testing.T) {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		// Both systems wait for each other, so they only finish if they run at the same time.
+		meet := func() error {
+			wg.Done()
+			ch := make(chan struct{})
+			go func() { wg.Wait(); close(ch) }()
+			select {
+			case <-ch:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("systems did not run concurrently")
+			}
+		}
+		s := scheduler.New(
+			&testSystem{access: writes(velocity.Type), update: meet},
+			&testSystem{access: writes(position.Type), update: meet},
+		)
+		if err := s.Update(); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+	})
+
+	t.Run("should run conflicting systems in order", func(t *testing.T) {
+		var mu sync.Mutex
+		var order []int
+		record := func(i int) func() error {
+			return func() error {
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, i)
+				return nil
+			}
+		}
+		s := scheduler.New(
+			&testSystem{access: writes(velocity.Type), update: record(0)},
+			&testSystem{access: reads(velocity.Type), update: record(1)},
+			&exclusiveSystem{update: record(2)},
+		)
+		if err := s.Update(); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if diff := cmp.Diff([]int{0, 1, 2}, order); diff != "" {
+			t.Errorf("order mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should not run systems that depend on a failed system", func(t *testing.T) {
+		wantErr := errors.New("failed")
+		var ran atomic.Bool
+		s := scheduler.New(
+			&testSystem{access: writes(velocity.Type), update: func() error { return wantErr }},
+			&testSystem{access: reads(velocity.Type), update: func() error { ran.Store(true); return nil }},
+		)
+		if err := s.Update(); !errors.Is(err, wantErr) {
+			t.Errorf("Update() error = %v, want %v", err, wantErr)
+		}
+		if ran.Load() {
+			t.Error("dependent system should not run")
+		}
+	})
+}
+
+// TestScheduler_Stress runs systems that change the ECS concurrently for many frames.
+// Run it with the race detector: go test -race ./scheduler/.
+func TestScheduler_Stress(t *testing.T) {
+	bus := event.NewBus()
+	ecs := ecsys.New(bus, ecsys.NewStores(ecsys.WithSparseSet[hitbox.Hitbox]()))
+	var events atomic.Int64
+	bus.Subscribe(event.MatcherFunc(func(event.Event) bool { return true }), func(event.Event) error {
+		events.Add(1)
+		return nil
+	})
+
+	const n = 50
+	entities := make([]entity.Entity, n)
+	for i := range entities {
+		e, err := ecs.CreateEntity(ecs.Root(), point.New(i, i))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		entities[i] = e
+	}
+
+	// Moves entities and reparents them.
+	mover := &testSystem{access: ecsys.Access{Writes: []component.Type{position.Type}}, update: func() error {
+		for i, e := range entities {
+			if err := ecsys.Mutate(ecs, e, func(p *position.Position) bool {
+				p.X++
+				if i > 0 && p.X%7 == 0 {
+					p.Parent = entities[i-1]
+				}
+				return true
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+	// Adds and removes velocities.
+	velocities := &testSystem{access: writes(velocity.Type), update: func() error {
+		for _, e := range entities {
+			if vs := ecsys.List[velocity.Velocity](ecs, e); len(vs) > 0 {
+				if err := ecsys.Delete(ecs, vs[0]); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := ecsys.Add(ecs, *velocity.New(e, point.New(1, 1))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+	// Creates and deletes entities with hitboxes.
+	var spawned []entity.Entity
+	spawner := &testSystem{access: writes(hitbox.Type, sprite.Type), update: func() error {
+		for _, e := range spawned {
+			if err := ecs.DeleteEntity(e); err != nil {
+				return err
+			}
+		}
+		spawned = spawned[:0]
+		for range 10 {
+			e, err := ecs.CreateEmptyEntity()
+			if err != nil {
+				return err
+			}
+			if _, err = ecsys.Add(ecs, *hitbox.New(e, "main", 1, 1, point.Zero())); err != nil {
+				return err
+			}
+			if _, err := ecsys.Add(ecs, *sprite.New(e, "s", sprite.SkeletonMoveDown1)); err != nil {
+				return err
+			}
+			spawned = append(spawned, e)
+		}
+		return nil
+	}}
+	// Reads the hierarchy and the hitboxes of other systems.
+	reader := &testSystem{access: reads(velocity.Type), update: func() error {
+		for _, e := range entities {
+			_, _ = ecs.GetAbsolutePosition(e)
+			_ = ecs.Children(e)
+			_ = ecs.Alive(e)
+		}
+		for range ecsys.Iter[velocity.Velocity](ecs) {
+		}
+		return nil
+	}}
+
+	s := scheduler.New(mover, velocities, spawner, reader)
+	for range 100 {
+		if err := s.Update(); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if err := ecs.EndFrame(); err != nil {
+			t.Fatalf("EndFrame() error = %v", err)
+		}
+	}
+	if events.Load() == 0 {
+		t.Error("expected events to be published")
+	}
+	if l := len(ecsys.All[hitbox.Hitbox](ecs)); l != 10 {
+		t.Errorf("All() len = %d, want 10", l)
+	}
+}
@@ -2,52 +2,51 @@ package collision
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"math"
 	"sync"
 
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/active"
 	"github.com/dwethmar/vork/component/hitbox"
 	"github.com/dwethmar/vork/component/position"
 	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/resource"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // System is a collision system.
 type System struct {
-	logger              *slog.Logger
-	ecs                 *ecsys.ECS
-	eventBus            *event.Bus
-	velocityScaleFactor int // Scale factor for the velocity
-	friction            int // Friction to apply to the velocity
-	velocityThreshold   int // Threshold for velocity to stop movement
-	subscriptions       []int
-	mux                 sync.RWMutex
-	moving              map[uint]*velocity.Velocity
+	logger            *slog.Logger
+	ecs               *ecsys.ECS
+	eventBus          *event.Bus
+	velocityThreshold int // Threshold for velocity to stop movement
+	subscriptions     []int
+	mux               sync.RWMutex
+	moving            map[uint]*velocity.Velocity
 }
 
 // Options for the collision system.
 type Options struct {
-	Logger              *slog.Logger
-	ECS                 *ecsys.ECS
-	EventBus            *event.Bus
-	VelocityScaleFactor int // Scale factor for the velocity
-	Friction            int // Friction to apply to the velocity
-	VelocityThreshold   int // Threshold for velocity to stop movement
+	Logger            *slog.Logger
+	ECS               *ecsys.ECS
+	EventBus          *event.Bus
+	VelocityThreshold int // Threshold for velocity to stop movement
 }
 
-// New creates a new collision system.
+// New creates a new collision system. The velocity scale factor and friction are read from the
+// GameSettings resource.
 func New(opts Options) *System {
 	return &System{
-		logger:              opts.Logger.With("system", "collision"),
-		ecs:                 opts.ECS,
-		eventBus:            opts.EventBus,
-		moving:              make(map[uint]*velocity.Velocity),
-		velocityScaleFactor: opts.VelocityScaleFactor,
-		friction:            opts.Friction,
-		velocityThreshold:   opts.VelocityThreshold,
+		logger:            opts.Logger.With("system", "collision"),
+		ecs:               opts.ECS,
+		eventBus:          opts.EventBus,
+		moving:            make(map[uint]*velocity.Velocity),
+		velocityThreshold: opts.VelocityThreshold,
 	}
 }
 
@@ -63,29 +62,35 @@ func (s *System) Init() error {
 		return errors.New("event bus is nil")
 	}
 
-	posEventsMatcher := event.MatchAny(velocity.CreatedEventType, velocity.UpdatedEventType)
 	s.subscriptions = []int{
-		s.eventBus.Subscribe(posEventsMatcher, s.onVelocityEvent),
+		ecsys.SubscribeComponent(s.eventBus, s.onVelocityChange, event.WithName("collision.velocity"), event.WithLoggedErrors()),
 	}
 	return nil
 }
 
-func (s *System) onVelocityEvent(event event.Event) error {
-	pe, ok := event.(velocity.Event)
-	if !ok {
-		return errors.New("event is not a position event")
-	}
-
-	s.logger.Debug("Position event received", slog.Any("entityID", pe.Velocity().ID()))
+func (s *System) onVelocityChange(c ecsys.ComponentChange[velocity.Velocity]) error {
+	s.logger.Debug("Velocity event received", slog.Any("entityID", c.Component.ID()))
 
-	if ve := pe.Velocity(); ve.Zero() || pe.Deleted() {
-		delete(s.moving, pe.Velocity().ID())
+	// Velocity events are published by other systems that may run concurrently.
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if ve := c.Component; ve.Zero() || c.Deleted {
+		delete(s.moving, ve.ID())
 	} else {
-		s.moving[pe.Velocity().ID()] = ve
+		s.moving[ve.ID()] = &ve
 	}
 	return nil
 }
 
+// Access returns the component types the system reads and writes.
+func (s *System) Access() ecsys.Access {
+	return ecsys.Access{
+		Reads:         []component.Type{hitbox.Type, active.Type},
+		Writes:        []component.Type{position.Type, velocity.Type},
+		ReadResources: []resource.Type{resource.GameSettingsType},
+	}
+}
+
 // Close closes the system.
 func (s *System) Close() error {
 	for _, id := range s.subscriptions {
@@ -97,25 +102,39 @@ func (s *System) Close() error {
 func (s *System) Update() error {
 	s.mux.Lock()
 	defer s.mux.Unlock()
+	if len(s.moving) == 0 {
+		return nil
+	}
+
+	settings, err := ecsys.GetResource[resource.GameSettings](s.ecs)
+	if err != nil {
+		return fmt.Errorf("failed to get game settings: %w", err)
+	}
 
 	for _, vel := range s.moving {
+		// Inactive entities keep their velocity until they are active again
+		if !s.ecs.Active(vel.Entity()) {
+			continue
+		}
+
 		// Get position of the entity associated with this velocity
-		pos, err := s.ecs.GetPosition(vel.Entity())
+		pos, err := ecsys.Get[position.Position](s.ecs, vel.Entity())
 		if err != nil {
 			return err
 		}
 
 		// Apply friction to velocity and scale
-		vel.X = (vel.X * s.friction) / s.velocityScaleFactor
-		vel.Y = (vel.Y * s.friction) / s.velocityScaleFactor
+		vel.X = (vel.X * settings.Friction) / settings.VelocityScaleFactor
+		vel.Y = (vel.Y * settings.Friction) / settings.VelocityScaleFactor
 
 		// If the velocity is too small, stop the movement
 		if abs(vel.X) < s.velocityThreshold && abs(vel.Y) < s.velocityThreshold {
 			vel.X = 0
 			vel.Y = 0
 
-			// Update the velocity component if it's effectively zero
-			if err = s.ecs.UpdateVelocityComponent(*vel); err != nil {
+			// Update the velocity component if it's effectively zero, its change event is
+			// published at the end of the frame so the handler does not run while s.mux is held.
+			if err = ecsys.MarkDirty(s.ecs, *vel); err != nil {
 				return err
 			}
 			continue
@@ -144,14 +163,10 @@ func (s *System) Update() error {
 
 func (s *System) collide(pos position.Position, velX, velY int) error {
 	// Get the hitbox of the moving entity
-	hbList := s.ecs.ListHitboxes(pos.Entity())
-	if len(hbList) == 0 {
-		return errors.New("no hitbox found for entity")
+	hb, err := ecsys.Get[hitbox.Hitbox](s.ecs, pos.Entity())
+	if err != nil {
+		return fmt.Errorf("no hitbox found for entity: %w", err)
 	}
-	hb := &hbList[0]
-
-	// Get all hitboxes
-	hbs := s.ecs.AllHitboxes()
 
 	// Store original position
 	origPos := pos
@@ -160,10 +175,7 @@ func (s *System) collide(pos position.Position, velX, velY int) error {
 	var collisionX, collisionY bool
 
 	// Check collision along X-axis
-	collisionX, err := s.checkCollision(pos, hb, hbs, velX, 0)
-	if err != nil {
-		return err
-	}
+	collisionX = s.checkCollision(pos, &hb, velX, 0)
 	if collisionX {
 		pos.X = origPos.X // Rollback X movement
 	} else {
@@ -171,10 +183,7 @@ func (s *System) collide(pos position.Position, velX, velY int) error {
 	}
 
 	// Check collision along Y-axis
-	collisionY, err = s.checkCollision(pos, hb, hbs, 0, velY)
-	if err != nil {
-		return err
-	}
+	collisionY = s.checkCollision(pos, &hb, 0, velY)
 	if collisionY {
 		pos.Y = origPos.Y // Rollback Y movement
 	} else {
@@ -183,21 +192,23 @@ func (s *System) collide(pos position.Position, velX, velY int) error {
 
 	// Update velocity after collision
 	if collisionX || collisionY {
-		if err = s.updateVelocityAfterCollision(pos.Entity(), collisionX, collisionY); err != nil {
+		if err := s.updateVelocityAfterCollision(pos.Entity(), collisionX, collisionY); err != nil {
 			return err
 		}
 	}
 
-	// Update position component
-	return s.ecs.UpdatePositionComponent(pos)
+	// Update position component, its change event is published at the end of the frame
+	if pos == origPos {
+		return nil
+	}
+	return ecsys.MarkDirty(s.ecs, pos)
 }
 
 func (s *System) checkCollision(
 	pos position.Position,
 	hb *hitbox.Hitbox,
-	hbs []hitbox.Hitbox,
 	deltaX, deltaY int,
-) (bool, error) {
+) bool {
 	// Move position by delta values
 	pos.X += deltaX
 	pos.Y += deltaY
@@ -205,35 +216,27 @@ func (s *System) checkCollision(
 	// Get moving entity's bounding box
 	movingBox := getBoundingBox(pos, hb)
 
-	// Check for collisions
-	for _, otherHb := range hbs {
-		if otherHb.Entity() == pos.Entity() {
+	// Check for collisions against all hitboxes with their positions
+	for other := range ecsys.IterQuery2[hitbox.Hitbox, position.Position](s.ecs) {
+		if other.Entity == pos.Entity() {
 			continue
 		}
 
-		otherPos, err := s.ecs.GetPosition(otherHb.Entity())
-		if err != nil {
-			if errors.Is(err, ecsys.ErrComponentNotFound) {
-				continue
-			}
-			return false, err
-		}
-
-		otherBox := getBoundingBox(otherPos, &otherHb)
+		otherBox := getBoundingBox(other.B, &other.A)
 
 		if boxesOverlap(movingBox, otherBox) {
-			return true, nil
+			return true
 		}
 	}
 
-	return false, nil
+	return false
 }
 
 func (s *System) updateVelocityAfterCollision(
 	entityID entity.Entity,
 	collisionX, collisionY bool,
 ) error {
-	vel, err := s.ecs.GetVelocity(entityID)
+	vel, err := ecsys.Get[velocity.Velocity](s.ecs, entityID)
 	if err != nil {
 		return err
 	}
@@ -245,8 +248,8 @@ func (s *System) updateVelocityAfterCollision(
 		vel.Y = 0
 	}
 
-	// Update the velocity component
-	return s.ecs.UpdateVelocityComponent(vel)
+	// Update the velocity component, its change event is published at the end of the frame
+	return ecsys.MarkDirty(s.ecs, vel)
 }
 
 // Utility function for absolute value.
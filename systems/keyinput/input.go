@@ -5,30 +5,34 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/active"
+	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/resource"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 // System is a controller system.
 type System struct {
-	logger              *slog.Logger
-	ecs                 *ecsys.ECS
-	velocityScaleFactor int
+	logger *slog.Logger
+	ecs    *ecsys.ECS
 }
 
 // Options is the options for the system.
 type Options struct {
-	Logger              *slog.Logger
-	ECS                 *ecsys.ECS
-	VelocityScaleFactor int
+	Logger *slog.Logger
+	ECS    *ecsys.ECS
 }
 
-// New creates a new keyinput system. It moves all controllable entities in the direction of the direction keys.
+// New creates a new keyinput system. It captures the input state of the frame in the InputState
+// resource and moves all controllable entities in the direction of the direction keys.
 func New(opts Options) *System {
 	return &System{
-		logger:              opts.Logger.With("system", "keyinput"),
-		ecs:                 opts.ECS,
-		velocityScaleFactor: opts.VelocityScaleFactor,
+		logger: opts.Logger.With("system", "keyinput"),
+		ecs:    opts.ECS,
 	}
 }
 
@@ -40,32 +44,67 @@ func (s *System) Init() error {
 	return nil
 }
 
+// Access returns the component types the system reads and writes.
+func (s *System) Access() ecsys.Access {
+	return ecsys.Access{
+		Reads:          []component.Type{controllable.Type, active.Type},
+		Writes:         []component.Type{velocity.Type},
+		ReadResources:  []resource.Type{resource.GameSettingsType},
+		WriteResources: []resource.Type{resource.InputStateType},
+	}
+}
+
 // Close closes the system.
 func (s *System) Close() error {
 	return nil
 }
 
 func (s *System) Update() error {
-	x, y := direction()
-	if x == 0 && y == 0 {
+	input := capture()
+	if err := ecsys.UpdateResource(s.ecs, func(i *resource.InputState) bool {
+		if *i == input {
+			return false
+		}
+		*i = input
+		return true
+	}); err != nil {
+		return fmt.Errorf("failed to update input state: %w", err)
+	}
+	if input.DirectionX == 0 && input.DirectionY == 0 {
 		return nil
 	}
-	for _, c := range s.ecs.AllControllables() {
-		v, err := s.ecs.GetVelocity(c.Entity())
-		if err != nil {
-			return fmt.Errorf("failed to get velocity component: %w", err)
-		}
-
-		v.X = x * s.velocityScaleFactor
-		v.Y = y * s.velocityScaleFactor
+	settings, err := ecsys.GetResource[resource.GameSettings](s.ecs)
+	if err != nil {
+		return fmt.Errorf("failed to get game settings: %w", err)
+	}
+	// Only the controllable store is held while iterating, so the velocities can be updated in the loop.
+	for r := range ecsys.IterQuery2[controllable.Controllable, velocity.Velocity](s.ecs) {
+		v := r.B
+		v.X = input.DirectionX * settings.VelocityScaleFactor
+		v.Y = input.DirectionY * settings.VelocityScaleFactor
 
-		if err = s.ecs.UpdateVelocityComponent(v); err != nil {
+		if err := ecsys.Update(s.ecs, v); err != nil {
 			return fmt.Errorf("failed to update velocity component: %w", err)
 		}
 	}
 	return nil
 }
 
+// capture returns the state of the input devices.
+func capture() resource.InputState {
+	x, y := direction()
+	cx, cy := ebiten.CursorPosition()
+	_, wheelY := ebiten.Wheel()
+	return resource.InputState{
+		DirectionX:  x,
+		DirectionY:  y,
+		CursorX:     cx,
+		CursorY:     cy,
+		LeftClicked: inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft),
+		WheelY:      wheelY,
+	}
+}
+
 func (s *System) Draw(_ *ebiten.Image) error {
 	return nil
 }
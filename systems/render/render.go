@@ -3,15 +3,20 @@ package render
 import (
 	"errors"
 	"fmt"
-	"image/color"
 	"log/slog"
 	"sort"
 
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/active"
+	"github.com/dwethmar/vork/component/controllable"
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/shape"
 	"github.com/dwethmar/vork/component/sprite"
 	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/point"
+	"github.com/dwethmar/vork/resource"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
@@ -35,9 +40,6 @@ type System struct {
 	logger       *slog.Logger
 	sprites      map[sprite.Graphic]*Sprite
 	ecs          *ecsys.ECS
-	offsetX      int
-	offsetY      int
-	zoom         float64
 	clickHandler MouseHandler
 	hoverHandler MouseHandler
 }
@@ -61,9 +63,6 @@ func New(opts Options) *System {
 		logger:       opts.Logger.With("system", "render"),
 		sprites:      spriteMap,
 		ecs:          opts.ECS,
-		offsetX:      0,
-		offsetY:      0,
-		zoom:         1.0,
 		clickHandler: opts.ClickHandler,
 		hoverHandler: opts.HoverHandler,
 	}
@@ -80,6 +79,15 @@ func (s *System) Init() error {
 	return nil
 }
 
+// Access returns the component types the system reads and writes.
+func (s *System) Access() ecsys.Access {
+	return ecsys.Access{
+		Reads:          []component.Type{controllable.Type, position.Type, active.Type},
+		ReadResources:  []resource.Type{resource.InputStateType},
+		WriteResources: []resource.Type{resource.CameraType},
+	}
+}
+
 // Close closes the system.
 func (s *System) Close() error {
 	return nil
@@ -93,61 +101,77 @@ type entityDraw struct {
 
 // Draw draws the entities on the screen.
 func (s *System) Draw(screen *ebiten.Image) error {
-	if err := renderGrid(screen, s.offsetX, s.offsetY, s.zoom, true); err != nil {
+	cam, err := s.camera()
+	if err != nil {
+		return err
+	}
+	if err = renderGrid(screen, cam.X, cam.Y, cam.Zoom, true); err != nil {
 		return err
 	}
 
+	view := viewMatrix(cam)
 	entitiesToDraw := []entityDraw{}
-	// Collect rectangles to draw
-	for _, r := range s.ecs.AllRectangles() {
-		pt, err := s.ecs.GetAbsolutePosition(r.Entity())
+	// Collect shapes to draw
+	addShape := func(e entity.Entity, path *vector.Path, style shape.Style, fill bool) error {
+		world, err := s.ecs.WorldTransform(e)
 		if err != nil {
-			return fmt.Errorf("could not get absolute position for entity %v: %w", r.Entity(), err)
+			return fmt.Errorf("could not get world transform for entity %v: %w", e, err)
 		}
+		_, y := world.Translation()
 
-		x, y := pt.Cords()
-
-		// Add the drawing function for this rectangle
+		// Add the drawing function for this shape
 		entitiesToDraw = append(entitiesToDraw, entityDraw{
-			Index: y,
+			Index: int(y),
 			DrawFunc: func(screen *ebiten.Image) {
-				// Apply zoom factor to position and size
-				x := (float32(x) - float32(s.offsetX)) * float32(s.zoom)
-				y := (float32(y) - float32(s.offsetY)) * float32(s.zoom)
-				width := float32(r.Width) * float32(s.zoom)
-				height := float32(r.Height) * float32(s.zoom)
-				vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{R: 0xff, G: 0x00, B: 0x00, A: 0xff}, true)
+				// Transform the path to the screen, applying rotation, scale and zoom
+				drawShape(screen, view.Mul(world), path, style, fill)
 			},
 		})
+		return nil
+	}
+	for e, r := range ecsys.Iter[shape.Rectangle](s.ecs) {
+		if err = addShape(e, rectanglePath(r), r.Style, true); err != nil {
+			return err
+		}
+	}
+	for e, c := range ecsys.Iter[shape.Circle](s.ecs) {
+		if err = addShape(e, circlePath(c), c.Style, true); err != nil {
+			return err
+		}
+	}
+	for e, l := range ecsys.Iter[shape.Line](s.ecs) {
+		if err = addShape(e, linePath(l), l.Style, false); err != nil {
+			return err
+		}
+	}
+	for e, p := range ecsys.Iter[shape.Polygon](s.ecs) {
+		if err = addShape(e, polygonPath(p), p.Style, true); err != nil {
+			return err
+		}
 	}
 
 	// Collect sprites to draw
-	for _, spc := range s.ecs.AllSprites() {
-		pt, err := s.ecs.GetAbsolutePosition(spc.Entity())
+	for e, spc := range ecsys.Iter[sprite.Sprite](s.ecs) {
+		world, err := s.ecs.WorldTransform(e)
 		if err != nil {
-			return fmt.Errorf("could not get absolute position for entity %v: %w", spc.Entity(), err)
+			return fmt.Errorf("could not get world transform for entity %v: %w", e, err)
 		}
 		spr, ok := s.sprites[spc.Graphic]
 		if !ok {
 			return fmt.Errorf("sprite not found: %s", spc.Graphic)
 		}
 
-		// Apply sprite offsets
-		pos := pt.AddPoint(spr.Offset)
-		x, y := pos.Cords()
+		// Apply sprite offsets in the local space of the entity
+		_, y := world.Apply(float64(spr.Offset.X), float64(spr.Offset.Y))
 
 		// Add the drawing function for this sprite
 		entitiesToDraw = append(entitiesToDraw, entityDraw{
-			Index: y,
+			Index: int(y),
 			DrawFunc: func(screen *ebiten.Image) {
-				// Apply zoom factor to position and scale
 				op := &ebiten.DrawImageOptions{}
-				// Scale the sprite
-				op.GeoM.Scale(s.zoom, s.zoom)
-				// Translate the sprite
-				x := (float64(x) - float64(s.offsetX)) * s.zoom
-				y := (float64(y) - float64(s.offsetY)) * s.zoom
-				op.GeoM.Translate(x, y)
+				// Offset the sprite, then rotate, scale and translate it to the screen
+				op.GeoM.Translate(float64(spr.Offset.X), float64(spr.Offset.Y))
+				op.GeoM.Concat(geoM(view.Mul(world)))
 				screen.DrawImage(spr.Img, op)
 			},
 		})
@@ -167,29 +191,35 @@ func (s *System) Draw(screen *ebiten.Image) error {
 }
 
 func (s *System) Update() error {
+	cam, err := s.camera()
+	if err != nil {
+		return err
+	}
+	input, err := ecsys.GetResource[resource.InputState](s.ecs)
+	if err != nil && !errors.Is(err, ecsys.ErrResourceNotFound) {
+		return fmt.Errorf("could not get input state: %w", err)
+	}
+	moved := cam
+
 	// Handle zoom in/out with mouse wheel
-	_, wheelY := ebiten.Wheel()
-	if wheelY != 0 {
-		if wheelY > 0 {
-			s.zoom *= zoomFactor
-		} else if wheelY < 0 {
-			s.zoom /= zoomFactor
+	if input.WheelY != 0 {
+		if input.WheelY > 0 {
+			moved.Zoom *= zoomFactor
+		} else if input.WheelY < 0 {
+			moved.Zoom /= zoomFactor
 		}
 		// Clamp zoom level to prevent extreme zooming
-		if s.zoom < minZoom {
-			s.zoom = minZoom
-		} else if s.zoom > maxZoom {
-			s.zoom = maxZoom
+		if moved.Zoom < minZoom {
+			moved.Zoom = minZoom
+		} else if moved.Zoom > maxZoom {
+			moved.Zoom = maxZoom
 		}
 	}
 
 	// get the first controllable entity and center the camera on it
-	if controllables := s.ecs.AllControllables(); len(controllables) > 0 {
-		// Get the first controllable entity
-		firstControllable := controllables[0]
-
+	for e := range ecsys.Iter[controllable.Controllable](s.ecs) {
 		// Get the position of the controllable
-		pt, err := s.ecs.GetAbsolutePosition(firstControllable.Entity())
+		pt, err := s.ecs.GetAbsolutePosition(e)
 		if err != nil {
 			return err
 		}
@@ -199,31 +229,40 @@ func (s *System) Update() error {
 		// Get the actual screen dimensions from Ebiten
 		screenWidth, screenHeight := ebiten.WindowSize()
 		// Calculate the offsets to center the controllable on the screen, accounting for zoom
-		s.offsetX = int(float64(x) - (float64(screenWidth) / (2 * s.zoom)))
-		s.offsetY = int(float64(y) - (float64(screenHeight) / (2 * s.zoom)))
+		moved.X = int(float64(x) - (float64(screenWidth) / (2 * moved.Zoom)))
+		moved.Y = int(float64(y) - (float64(screenHeight) / (2 * moved.Zoom)))
+		break
+	}
+
+	if moved != cam {
+		if err = s.ecs.InsertResource(moved); err != nil {
+			return fmt.Errorf("could not update camera: %w", err)
+		}
 	}
 
 	// Handle mouse click
-	if s.clickHandler != nil && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	if s.clickHandler != nil && input.LeftClicked {
 		// Apply zoom factor to mouse position
-		x, y := s.applyZoom(ebiten.CursorPosition())
-		s.clickHandler(x, y)
+		s.clickHandler(moved.ScreenToWorld(input.CursorX, input.CursorY))
 	}
 
 	// Handle mouse hover
 	if s.hoverHandler != nil {
 		// Apply zoom factor to mouse position
-		x, y := s.applyZoom(ebiten.CursorPosition())
-		s.hoverHandler(x, y)
+		s.hoverHandler(moved.ScreenToWorld(input.CursorX, input.CursorY))
 	}
 
 	return nil
 }
 
-// applyZoom applies the zoom factor to the given position.
-func (s *System) applyZoom(x, y int) (int, int) {
-	// Apply zoom factor to position
-	x = int(float64(x)/s.zoom) + s.offsetX
-	y = int(float64(y)/s.zoom) + s.offsetY
-	return x, y
+// camera returns the camera resource, or a camera at the origin if the ECS has none.
+func (s *System) camera() (resource.Camera, error) {
+	cam, err := ecsys.GetResource[resource.Camera](s.ecs)
+	if errors.Is(err, ecsys.ErrResourceNotFound) {
+		return resource.NewCamera(), nil
+	}
+	if err != nil {
+		return cam, fmt.Errorf("could not get camera: %w", err)
+	}
+	return cam, nil
 }
@@ -1,11 +1,14 @@
 package render_test
 
 import (
+	"image/color"
 	"log/slog"
 	"testing"
 
+	"github.com/dwethmar/vork/component/shape"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/event"
+	"github.com/dwethmar/vork/point"
 	"github.com/dwethmar/vork/systems/render"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -57,6 +60,42 @@ func TestSystem_Draw(t *testing.T) {
 			t.Errorf("Draw() = %v, want nil", err)
 		}
 	})
+
+	t.Run("Draw shapes", func(t *testing.T) {
+		eventBus := event.NewBus()
+		ecs := ecsys.New(eventBus, ecsys.NewStores())
+		s := render.New(render.Options{
+			Logger:       slog.Default(),
+			Sprites:      []render.Sprite{},
+			ECS:          ecs,
+			ClickHandler: func(_, _ int) {},
+		})
+		e, err := ecs.CreateEntity(ecs.Root(), point.New(10, 10))
+		if err != nil {
+			t.Fatalf("CreateEntity() error = %v", err)
+		}
+		clr := color.NRGBA{R: 0xff, G: 0x40, A: 0x80}
+		rect := shape.NewRectangle(e, 10, 10, clr)
+		rect.Stroke, rect.StrokeWidth = color.NRGBA{A: 0xff}, 2
+		if _, err = ecsys.Add(ecs, *rect); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *shape.NewCircle(e, 5, clr)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *shape.NewLine(e, point.New(20, 5), clr, 1)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if _, err = ecsys.Add(ecs, *shape.NewPolygon(e, clr, point.Zero(), point.New(5, 0), point.New(0, 5))); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+
+		screen := ebiten.NewImage(100, 100)
+
+		if err = s.Draw(screen); err != nil {
+			t.Errorf("Draw() = %v, want nil", err)
+		}
+	})
 }
 
 func TestSystem_Update(t *testing.T) {
@@ -0,0 +1,99 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/dwethmar/vork/component/shape"
+	"github.com/dwethmar/vork/component/transform"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var (
+	whiteImage    = ebiten.NewImage(3, 3)
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImage.Fill(color.White)
+}
+
+// rectanglePath returns the outline of the rectangle in the local space of its entity.
+func rectanglePath(r shape.Rectangle) *vector.Path {
+	w, h := float32(r.Width), float32(r.Height)
+	var path vector.Path
+	path.MoveTo(0, 0)
+	path.LineTo(w, 0)
+	path.LineTo(w, h)
+	path.LineTo(0, h)
+	path.Close()
+	return &path
+}
+
+// circlePath returns the outline of the circle in the local space of its entity.
+func circlePath(c shape.Circle) *vector.Path {
+	var path vector.Path
+	path.Arc(0, 0, float32(c.Radius), 0, 2*math.Pi, vector.Clockwise)
+	path.Close()
+	return &path
+}
+
+// linePath returns the line in the local space of its entity.
+func linePath(l shape.Line) *vector.Path {
+	var path vector.Path
+	path.MoveTo(0, 0)
+	path.LineTo(float32(l.To.X), float32(l.To.Y))
+	return &path
+}
+
+// polygonPath returns the outline of the polygon in the local space of its entity.
+func polygonPath(p shape.Polygon) *vector.Path {
+	var path vector.Path
+	for i, pt := range p.Points {
+		if i == 0 {
+			path.MoveTo(float32(pt.X), float32(pt.Y))
+		} else {
+			path.LineTo(float32(pt.X), float32(pt.Y))
+		}
+	}
+	path.Close()
+	return &path
+}
+
+// drawShape fills and strokes the path with the style. The path is in the local space of
+// the entity and transformed by m, so the stroke width scales along with the shape.
+func drawShape(dst *ebiten.Image, m transform.Matrix, path *vector.Path, style shape.Style, fill bool) {
+	if fill && style.HasFill() {
+		vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+		drawVertices(dst, m, vs, is, style.Fill)
+	}
+	if style.HasStroke() {
+		vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+			Width:      float32(style.StrokeWidth),
+			MiterLimit: 10,
+		})
+		drawVertices(dst, m, vs, is, style.Stroke)
+	}
+}
+
+// drawVertices transforms the vertices by m and draws them with the colour.
+func drawVertices(dst *ebiten.Image, m transform.Matrix, vs []ebiten.Vertex, is []uint16, clr color.Color) {
+	r, g, b, a := clr.RGBA()
+	for i := range vs {
+		x, y := m.Apply(float64(vs[i].DstX), float64(vs[i].DstY))
+		vs[i].DstX, vs[i].DstY = float32(x), float32(y)
+		vs[i].SrcX = 1
+		vs[i].SrcY = 1
+		vs[i].ColorR = float32(r) / 0xffff
+		vs[i].ColorG = float32(g) / 0xffff
+		vs[i].ColorB = float32(b) / 0xffff
+		vs[i].ColorA = float32(a) / 0xffff
+	}
+	op := &ebiten.DrawTrianglesOptions{}
+	// the colours of a style are not premultiplied, but RGBA returns premultiplied values
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.AntiAlias = true
+	dst.DrawTriangles(vs, is, whiteSubImage, op)
+}
@@ -0,0 +1,29 @@
+package render
+
+import (
+	"github.com/dwethmar/vork/component/transform"
+	"github.com/dwethmar/vork/resource"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// viewMatrix returns the transform from the world to the screen.
+func viewMatrix(cam resource.Camera) transform.Matrix {
+	return transform.Matrix{
+		A:  cam.Zoom,
+		D:  cam.Zoom,
+		Tx: -float64(cam.X) * cam.Zoom,
+		Ty: -float64(cam.Y) * cam.Zoom,
+	}
+}
+
+// geoM converts the matrix to an ebiten.GeoM.
+func geoM(m transform.Matrix) ebiten.GeoM {
+	var g ebiten.GeoM
+	g.SetElement(0, 0, m.A)
+	g.SetElement(0, 1, m.C)
+	g.SetElement(0, 2, m.Tx)
+	g.SetElement(1, 0, m.B)
+	g.SetElement(1, 1, m.D)
+	g.SetElement(1, 2, m.Ty)
+	return g
+}
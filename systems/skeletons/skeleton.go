@@ -5,14 +5,19 @@ import (
 	"fmt"
 	"image/color"
 	"log/slog"
+	"sync"
 
+	"github.com/dwethmar/vork/component"
+	"github.com/dwethmar/vork/component/active"
 	"github.com/dwethmar/vork/component/hitbox"
+	"github.com/dwethmar/vork/component/position"
 	"github.com/dwethmar/vork/component/shape"
 	"github.com/dwethmar/vork/component/skeleton"
 	"github.com/dwethmar/vork/component/sprite"
 	"github.com/dwethmar/vork/component/velocity"
 	"github.com/dwethmar/vork/direction"
 	"github.com/dwethmar/vork/ecsys"
+	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
 	"github.com/dwethmar/vork/event/mouse"
 	"github.com/dwethmar/vork/point"
@@ -22,13 +27,25 @@ import (
 const (
 	walkAnimationPerFrames = 4
 	walkAnimationSteps     = 8 * walkAnimationPerFrames // frames every 8 steps (3 frames per step)
+	spriteTag              = "skeleton"                 // tag of the sprite of a skeleton
+	maxSetupAttempts       = 3                          // times a failing skeleton setup is tried before it is dropped
 )
 
+// setup is a skeleton waiting to be set up.
+type setup struct {
+	entity   entity.Entity
+	attempts int
+}
+
 // System is a system that manages skeletons in the game.
 type System struct {
 	logger        *slog.Logger
 	ecs           *ecsys.ECS
 	eventBus      *event.Bus
+	commands      *ecsys.CommandBuffer
+	mux           sync.Mutex
+	setups        []setup             // skeletons created since the last update
+	changed       []skeleton.Skeleton // skeletons changed during an update, reused every frame
 	subscriptions []int
 }
 
@@ -38,19 +55,15 @@ func New(logger *slog.Logger, ecs *ecsys.ECS, eventBus *event.Bus) *System {
 		logger:        logger.With("system", "skeletons"),
 		ecs:           ecs,
 		eventBus:      eventBus,
+		commands:      ecsys.NewCommandBuffer(ecs),
 		subscriptions: []int{},
 	}
 
 	// Subscribe to the skeleton events
-	s.subscriptions = append(s.subscriptions, s.eventBus.Subscribe(
-		event.MatchAny(skeleton.UpdatedEventType, skeleton.CreatedEventType, skeleton.DeletedEventType),
-		s.skeletonCreatedHandler,
-	))
-
-	s.subscriptions = append(s.subscriptions, s.eventBus.Subscribe(
-		event.MatchAny(mouse.LeftMouseClickedEventType),
-		s.skeletonCreatedHandler,
-	))
+	s.subscriptions = append(s.subscriptions,
+		ecsys.SubscribeComponent(s.eventBus, s.skeletonChangedHandler, event.WithName("skeletons.skeleton")),
+		event.Subscribe(s.eventBus, s.clickedHandler, event.WithName("skeletons.click"), event.WithLoggedErrors()),
+	)
 
 	return s
 }
@@ -64,14 +77,23 @@ func (s *System) Init() error {
 		return errors.New("eventBus is nil")
 	}
 	// Setup existing skeletons
-	for _, sk := range s.ecs.AllSkeletons() {
-		if err := s.setupSkeleton(sk); err != nil {
+	for _, sk := range ecsys.Iter[skeleton.Skeleton](s.ecs, ecsys.IncludeInactive()) {
+		if err := s.applySetup(sk); err != nil {
 			return fmt.Errorf("could not setup skeleton (%v): %w", sk.Entity(), err)
 		}
 	}
 	return nil
 }
 
+// Access returns the component types the system reads and writes.
+// Next to the skeletons and their sprites, setting up a skeleton adds rectangles, hitboxes and velocities.
+func (s *System) Access() ecsys.Access {
+	return ecsys.Access{
+		Reads:  []component.Type{position.Type, active.Type},
+		Writes: []component.Type{skeleton.Type, sprite.Type, shape.RectangleType, hitbox.Type, velocity.Type},
+	}
+}
+
 // Close closes the system.
 func (s *System) Close() error {
 	for _, sub := range s.subscriptions {
@@ -80,45 +102,82 @@ func (s *System) Close() error {
 	return nil
 }
 
-func (s *System) skeletonCreatedHandler(e event.Event) error {
-	switch e := e.(type) {
-	case *skeleton.CreatedEvent:
-		s.logger.Debug("skeleton created", "skeleton", e.Skeleton)
-		if err := s.setupSkeleton(*e.Skeleton()); err != nil {
-			return err
-		}
-	case *skeleton.UpdatedEvent:
-		s.logger.Debug("skeleton updated", "skeleton", e.Skeleton)
-	case *skeleton.DeletedEvent:
-		s.logger.Debug("skeleton deleted", "skeleton", e.Skeleton)
-	case *mouse.LeftClickedEvent:
-		s.logger.Info("clicked", "x", e.X, "y", e.Y)
-	default:
-		return fmt.Errorf("unhandled event type %T", e)
+func (s *System) skeletonChangedHandler(c ecsys.ComponentChange[skeleton.Skeleton]) error {
+	switch c.Event {
+	case skeleton.CreatedEventType:
+		s.logger.Debug("skeleton created", "skeleton", c.Component)
+		s.mux.Lock()
+		s.setups = append(s.setups, setup{entity: c.Component.Entity()})
+		s.mux.Unlock()
+	case skeleton.UpdatedEventType:
+		s.logger.Debug("skeleton updated", "skeleton", c.Component)
+	case skeleton.DeletedEventType:
+		s.logger.Debug("skeleton deleted", "skeleton", c.Component)
 	}
 	return nil
 }
 
-// setupSkeleton adds the necessary components to the entity to make it a skeleton.
-func (s *System) setupSkeleton(sk skeleton.Skeleton) error {
-	e := sk.Entity()
-	rect := shape.NewRectangle(e, 10, 10, color.RGBA{R: 0xff, G: 0x00, B: 0x00, A: 0xff})
-	if _, err := s.ecs.AddRectangle(*rect); err != nil {
-		return fmt.Errorf("could not add rectangle component to entity %v: %w", e, err)
+func (s *System) clickedHandler(e *mouse.LeftClickedEvent) error {
+	s.logger.Info("clicked", "x", e.X, "y", e.Y)
+	return nil
+}
+
+// applySetups sets up the skeletons created since the last update. Every skeleton is set up on its own,
+// so one failing setup does not drop the others. A failed setup is tried again at the next update.
+func (s *System) applySetups() {
+	s.mux.Lock()
+	setups := s.setups
+	s.setups = nil
+	s.mux.Unlock()
+
+	var retry []setup
+	for _, st := range setups {
+		sk, err := ecsys.Get[skeleton.Skeleton](s.ecs, st.entity)
+		if errors.Is(err, ecsys.ErrEntityNotFound) || errors.Is(err, ecsys.ErrComponentNotFound) {
+			continue // deleted before it was set up
+		}
+		if err == nil {
+			err = s.applySetup(sk)
+		}
+		if err == nil {
+			continue
+		}
+		st.attempts++
+		if st.attempts >= maxSetupAttempts {
+			s.logger.Error("could not setup skeleton, giving up", "entity", st.entity, "attempts", st.attempts, "error", err)
+			continue
+		}
+		s.logger.Warn("could not setup skeleton, retrying", "entity", st.entity, "attempts", st.attempts, "error", err)
+		retry = append(retry, st)
 	}
-	if _, err := s.ecs.AddSprite(*sprite.New(e, "skeleton", sprite.SkeletonMoveDown1)); err != nil {
-		return fmt.Errorf("could not add sprite component to entity %v: %w", e, err)
+
+	if len(retry) > 0 {
+		s.mux.Lock()
+		s.setups = append(retry, s.setups...)
+		s.mux.Unlock()
 	}
-	if _, err := s.ecs.AddHitbox(*hitbox.New(e, "main", 16, 16, point.New(-8, -8))); err != nil {
-		return fmt.Errorf("could not add hitbox component to entity %v: %w", e, err)
+}
+
+// applySetup adds the components that make the entity a skeleton in a single transaction.
+func (s *System) applySetup(sk skeleton.Skeleton) error {
+	if err := s.setupSkeleton(sk); err != nil {
+		s.commands.Reset()
+		return err
 	}
+	return s.commands.Apply()
+}
+
+// setupSkeleton records the components that make the entity a skeleton.
+// They are added when the command buffer is applied.
+func (s *System) setupSkeleton(sk skeleton.Skeleton) error {
+	e := sk.Entity()
+	s.commands.Add(shape.NewRectangle(e, 10, 10, color.NRGBA{R: 0xff, G: 0x00, B: 0x00, A: 0xff}))
+	s.commands.Add(sprite.New(e, spriteTag, sprite.SkeletonMoveDown1))
+	s.commands.Add(hitbox.New(e, "main", 16, 16, point.New(-8, -8)))
 	// ensure velocity component is present
-	if _, err := s.ecs.GetVelocity(e); err != nil {
+	if _, err := ecsys.Get[velocity.Velocity](s.ecs, e); err != nil {
 		if errors.Is(err, ecsys.ErrEntityNotFound) || errors.Is(err, ecsys.ErrComponentNotFound) {
-			vel := velocity.New(e, point.Zero())
-			if _, err = s.ecs.AddVelocity(*vel); err != nil {
-				return fmt.Errorf("could not add velocity component to entity %v: %w", e, err)
-			}
+			s.commands.Add(velocity.New(e, point.Zero()))
 		} else {
 			return fmt.Errorf("could not get velocity component for entity %v: %w", e, err)
 		}
@@ -132,35 +191,38 @@ func (s *System) Draw(_ *ebiten.Image) error {
 }
 
 // Update updates the skeletons in the ECS.
+// Only skeletons and sprites that changed are marked dirty, their change events are published at the end of the frame.
 func (s *System) Update() error {
-	skeletons := s.ecs.AllSkeletons()
-	for i := range skeletons {
-		e := &skeletons[i]
-		if err := s.updateSkeleton(e); err != nil {
-			return err
-		}
+	// Set up the skeletons created since the last update.
+	s.applySetups()
 
-		// Update the skeleton component in the ECS
-		if err := s.ecs.UpdateSkeletonComponent(*e); err != nil {
-			return fmt.Errorf("could not update skeleton: %w", err)
+	// The skeleton store is read-locked while iterating, changed skeletons are written back afterwards.
+	s.changed = s.changed[:0]
+	for r := range ecsys.IterQuery2[skeleton.Skeleton, position.Position](s.ecs) {
+		e := r.A
+		s.updateSkeleton(&e, r.B)
+
+		if e != r.A {
+			s.changed = append(s.changed, e)
 		}
 
 		// Move the sprite updating code to a separate function
-		if err := s.updateSprite(e); err != nil {
+		if err := s.updateSprite(&e); err != nil {
 			return err
 		}
 	}
 
+	for _, e := range s.changed {
+		if err := ecsys.MarkDirty(s.ecs, e); err != nil {
+			return fmt.Errorf("could not update skeleton: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // updateSkeleton applies skeleton behavior to the entity.
-func (s *System) updateSkeleton(e *skeleton.Skeleton) error {
-	pos, err := s.ecs.GetPosition(e.Entity())
-	if err != nil {
-		return fmt.Errorf("could not get position: %w", err)
-	}
-
+func (s *System) updateSkeleton(e *skeleton.Skeleton, pos position.Position) {
 	isMoving := false
 	x, y := pos.Cords()
 	if e.PrefX != x || e.PrefY != y {
@@ -187,26 +249,17 @@ func (s *System) updateSkeleton(e *skeleton.Skeleton) error {
 			e.AnimationStep = 0
 		}
 	}
-
-	return nil
 }
 
 // updateSprite updates the sprite associated with the skeleton based on its state and facing direction.
 func (s *System) updateSprite(e *skeleton.Skeleton) error {
 	// Retrieve the sprite component associated with the skeleton
-	var spr *sprite.Sprite
-	sprites := s.ecs.ListSprites(e.Entity())
-	for i := range sprites {
-		sp := &sprites[i]
-		if sp.Tag == "skeleton" {
-			spr = sp
-			break // Break early once found
-		}
-	}
-	if spr == nil {
-		s.logger.Error("could not find sprite", "entity", e.Entity())
-		return fmt.Errorf("could not find sprite for entity %v", e.Entity())
+	sprites := ecsys.LookupByEntity[sprite.Sprite](s.ecs, e.Entity(), sprite.TagIndex, spriteTag)
+	if len(sprites) == 0 {
+		// The skeleton has not been set up yet.
+		return nil
 	}
+	spr := &sprites[0]
 
 	// Determine the appropriate graphic based on state and facing direction
 	step := int(e.AnimationStep / walkAnimationPerFrames) // 3 frames per step
@@ -248,7 +301,7 @@ func (s *System) updateSprite(e *skeleton.Skeleton) error {
 	// Update the sprite's graphic if it has changed
 	if spr.Graphic != graphic {
 		spr.Graphic = graphic
-		if err := s.ecs.UpdateSpriteComponent(*spr); err != nil {
+		if err := ecsys.MarkDirty(s.ecs, *spr); err != nil {
 			return fmt.Errorf("could not update skeleton sprite: %w", err)
 		}
 	}
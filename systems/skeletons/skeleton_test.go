@@ -4,7 +4,10 @@ import (
 	"log/slog"
 	"testing"
 
+	"github.com/dwethmar/vork/component/position"
+	"github.com/dwethmar/vork/component/shape"
 	"github.com/dwethmar/vork/component/skeleton"
+	"github.com/dwethmar/vork/component/sprite"
 	"github.com/dwethmar/vork/ecsys"
 	"github.com/dwethmar/vork/entity"
 	"github.com/dwethmar/vork/event"
@@ -39,18 +42,23 @@ func TestNew(t *testing.T) {
 			t.Errorf("Publish() error = %v", err)
 		}
 
+		// the setup is applied at the next update
+		if err = s.Update(); err != nil {
+			t.Errorf("Update() error = %v", err)
+		}
+
 		// should have position
-		if _, err = ecs.GetPosition(e); err != nil {
+		if _, err = ecsys.Get[position.Position](ecs, e); err != nil {
 			t.Errorf("Expected position component, got %v", err)
 		}
 
 		// should have rectangle
-		if len(ecs.ListRectangles(e)) == 0 {
-			t.Errorf("Expected rectangle component, got %v", ecs.ListRectangles(e))
+		if len(ecsys.List[shape.Rectangle](ecs, e)) == 0 {
+			t.Errorf("Expected rectangle component, got %v", ecsys.List[shape.Rectangle](ecs, e))
 		}
 
 		// should have sprite
-		if r := ecs.ListSprites(e); len(r) == 0 {
+		if r := ecsys.List[sprite.Sprite](ecs, e); len(r) == 0 {
 			t.Errorf("Expected sprite component, got %v", r)
 		}
 	})